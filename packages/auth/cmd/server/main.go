@@ -14,12 +14,12 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/redis/go-redis/v9"
 	authv1 "github.com/yaninyzwitty/chat/gen/auth/v1"
 	"github.com/yaninyzwitty/chat/packages/auth/controller"
 	"github.com/yaninyzwitty/chat/packages/auth/jwt"
 	"github.com/yaninyzwitty/chat/packages/shared/config"
 	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/shared/redisclient"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -80,7 +80,7 @@ func run(ctx context.Context) error {
 		return errors.New("REDIS_URL environment variable is not set")
 	}
 
-	redisClient, err := generateRedisClient(redisURL)
+	redisClient, err := redisclient.Connect(redisURL)
 	if err != nil {
 		return fmt.Errorf("failed to create redis client: %w", err)
 	}
@@ -137,12 +137,3 @@ func run(ctx context.Context) error {
 
 	return errorGroup.Wait()
 }
-
-func generateRedisClient(redisUrl string) (*redis.Client, error) {
-	opt, err := redis.ParseURL(redisUrl)
-	if err != nil {
-		return nil, err
-	}
-	client := redis.NewClient(opt)
-	return client, nil
-}
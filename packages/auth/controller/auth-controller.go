@@ -51,9 +51,11 @@ func (c *AuthController) Login(ctx context.Context, req *authv1.LoginRequest) (*
 
 	var userID gocql.UUID
 	var username, hashedPassword string
+	var roles []string
+	var account accountState
 
-	query := "SELECT id, name, password FROM chat.users WHERE email = ? LIMIT 1"
-	if err := c.Db.Query(query, req.Email).Consistency(gocql.One).Scan(&userID, &username, &hashedPassword); err != nil {
+	query := "SELECT id, name, password, roles, suspended_at, suspended_until, suspension_reason FROM chat.users WHERE email = ? LIMIT 1"
+	if err := c.Db.Query(query, req.Email).Consistency(gocql.One).Scan(&userID, &username, &hashedPassword, &roles, &account.suspendedAt, &account.suspendedUntil, &account.reason); err != nil {
 		c.observeError(op, "cassandra")
 		return nil, status.Errorf(codes.Internal, "invalid credentials %v", err)
 	}
@@ -63,7 +65,12 @@ func (c *AuthController) Login(ctx context.Context, req *authv1.LoginRequest) (*
 		return nil, status.Errorf(codes.Internal, "failed to compare hash and password %v", err)
 	}
 
-	tokens, err := myJwt.GenerateJWTPair(userID.String(), username, req.Email, []string{"user"})
+	// checked after the password so suspension status is not disclosed to strangers
+	if err := account.check(); err != nil {
+		return nil, err
+	}
+
+	tokens, err := myJwt.GenerateJWTPair(userID.String(), username, req.Email, userRoles(roles))
 	if err != nil {
 		c.observeError(op, "jwt")
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
@@ -91,6 +98,7 @@ func (c *AuthController) RefreshToken(ctx context.Context, req *authv1.RefreshTo
 
 	eg, egCtx := errgroup.WithContext(ctx)
 	var username, email string
+	var roles []string
 
 	eg.Go(func() error {
 		valid, err := c.RefreshTokenStore.ValidateRefreshToken(egCtx, req.UserId, req.RefreshToken)
@@ -104,21 +112,25 @@ func (c *AuthController) RefreshToken(ctx context.Context, req *authv1.RefreshTo
 	})
 
 	eg.Go(func() error {
-		query := "SELECT name, email FROM chat.users WHERE id = ? LIMIT 1"
+		var account accountState
+		query := "SELECT name, email, roles, suspended_at, suspended_until, suspension_reason FROM chat.users WHERE id = ? LIMIT 1"
 		if err := c.Db.Query(query, req.UserId).
 			Consistency(gocql.One).
-			Scan(&username, &email); err != nil {
+			Scan(&username, &email, &roles, &account.suspendedAt, &account.suspendedUntil, &account.reason); err != nil {
 			return fmt.Errorf("invalid user: %w", err)
 		}
-		return nil
+		return account.check()
 	})
 
 	if err := eg.Wait(); err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return nil, err
+		}
 		c.observeError(op, "cassandra")
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
-	tokens, err := myJwt.GenerateJWTPair(req.UserId, username, email, []string{"user"})
+	tokens, err := myJwt.GenerateJWTPair(req.UserId, username, email, userRoles(roles))
 	if err != nil {
 		c.observeError(op, "jwt")
 		return nil, status.Errorf(codes.Internal, "failed to generate access token: %v", err)
@@ -177,6 +189,35 @@ func (c *AuthController) Logout(ctx context.Context, req *authv1.LogoutRequest)
 	return &authv1.LogoutResponse{Success: true}, nil
 }
 
+// accountState is the moderation state of a chat.users row.
+type accountState struct {
+	suspendedAt    time.Time
+	suspendedUntil time.Time
+	reason         string
+}
+
+// check rejects accounts under a suspension that has not yet lapsed.
+func (a accountState) check() error {
+	if a.suspendedAt.IsZero() {
+		return nil
+	}
+	if a.suspendedUntil.IsZero() {
+		return status.Errorf(codes.PermissionDenied, "account suspended: %s", a.reason)
+	}
+	if time.Now().Before(a.suspendedUntil) {
+		return status.Errorf(codes.PermissionDenied, "account suspended until %s: %s", a.suspendedUntil.UTC().Format(time.RFC3339), a.reason)
+	}
+	return nil
+}
+
+// userRoles defaults accounts without explicit roles to plain users.
+func userRoles(roles []string) []string {
+	if len(roles) == 0 {
+		return []string{"user"}
+	}
+	return roles
+}
+
 // --- helpers for metrics ---
 func (c *AuthController) observeDuration(op, db string, start time.Time) {
 	c.M.Duration.WithLabelValues(op, db).Observe(time.Since(start).Seconds())
@@ -21,38 +21,77 @@ var publicRoutes = map[string]struct{}{
 	"CreateUser":   {},
 }
 
+// ClaimsFromContext returns the claims AuthInterceptor injected into ctx.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(UserContextKey).(*Claims)
+	return claims, ok && claims != nil
+}
+
 // AuthInterceptor returns a gRPC unary interceptor for authentication
 func AuthInterceptor() grpc.UnaryServerInterceptor {
 
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		// gRPC full method is /package.Service/Method
-		parts := strings.Split(info.FullMethod, "/")
-		if len(parts) != 3 {
-			return nil, status.Error(codes.Unauthenticated, "invalid gRPC method")
+		ctx, err = authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
-		methodName := parts[2]
 
-		// skip auth for public routes
-		if _, ok := publicRoutes[methodName]; ok {
-			return handler(ctx, req)
-		}
+		// call the handler with the updated context
+		return handler(ctx, req)
+	}
+}
 
-		// extract bearer token from metadata
-		token, err := AuthFromMD(ctx, "bearer")
-		if err != nil {
-			return nil, status.Errorf(codes.Unauthenticated, "failed to extract authorization header: %v", err)
-		}
+// StreamAuthInterceptor returns a gRPC stream interceptor applying the same
+// rules as AuthInterceptor
+func StreamAuthInterceptor() grpc.StreamServerInterceptor {
 
-		// validate JWT token
-		claims, err := ValidateJWT(token)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod)
 		if err != nil {
-			return nil, status.Errorf(codes.Unauthenticated, "failed to validate JWT token: %v", err)
+			return err
 		}
 
-		// inject user info (claims) into context
-		ctx = context.WithValue(ctx, UserContextKey, claims)
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
 
-		// call the handler with the updated context
-		return handler(ctx, req)
+// authenticatedStream exposes the claims-carrying context to stream handlers
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate validates the bearer token for fullMethod and returns ctx with
+// the caller's claims injected; public routes pass through unchanged
+func authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	// gRPC full method is /package.Service/Method
+	parts := strings.Split(fullMethod, "/")
+	if len(parts) != 3 {
+		return nil, status.Error(codes.Unauthenticated, "invalid gRPC method")
+	}
+	methodName := parts[2]
+
+	// skip auth for public routes
+	if _, ok := publicRoutes[methodName]; ok {
+		return ctx, nil
+	}
+
+	// extract bearer token from metadata
+	token, err := AuthFromMD(ctx, "bearer")
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to extract authorization header: %v", err)
 	}
+
+	// validate JWT token
+	claims, err := ValidateJWT(token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to validate JWT token: %v", err)
+	}
+
+	// inject user info (claims) into context
+	return context.WithValue(ctx, UserContextKey, claims), nil
 }
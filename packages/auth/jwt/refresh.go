@@ -87,6 +87,21 @@ func (r *RefreshTokenStore) ValidateRefreshToken(ctx context.Context, userID str
 	return val == token, nil
 }
 
+// RefreshTokenExpiry reports when userID's refresh token expires, and false
+// if they have none.
+func (r *RefreshTokenStore) RefreshTokenExpiry(ctx context.Context, userID string) (time.Time, bool, error) {
+	key := fmt.Sprintf("refresh:%s", userID)
+	ttl, err := r.Redis.TTL(ctx, key).Result()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	// -2 means no key; -1 a key without expiry, which this store never writes
+	if ttl < 0 {
+		return time.Time{}, false, nil
+	}
+	return time.Now().Add(ttl), true, nil
+}
+
 func (r *RefreshTokenStore) DeleteRefreshToken(ctx context.Context, userID string) error {
 	key := fmt.Sprintf("refresh:%s", userID)
 	return r.Redis.Del(ctx, key).Err()
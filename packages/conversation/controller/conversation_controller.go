@@ -0,0 +1,412 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gocql/gocql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	"github.com/yaninyzwitty/chat/packages/conversation/handler"
+	"github.com/yaninyzwitty/chat/packages/conversation/roles"
+	"github.com/yaninyzwitty/chat/packages/shared/blocklist"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/shared/pagetoken"
+	"github.com/yaninyzwitty/chat/packages/shared/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultMaxGroupMembers = 256
+	defaultMaxNameLength   = 100
+	defaultMaxPageSize     = 200
+)
+
+type ConversationController struct {
+	conversationv1.UnimplementedConversationServiceServer
+	h      *handler.ConversationHandler
+	pages  *pagetoken.Codec
+	events *events.Log
+	M      *monitoring.Metrics
+	Config *config.Config
+}
+
+func NewConversationController(cfg *config.Config, reg *prometheus.Registry, db *gocql.Session, rdb *redis.Client, pages *pagetoken.Codec) *ConversationController {
+	return &ConversationController{
+		Config: cfg,
+		M:      monitoring.NewMetrics(reg),
+		h:      handler.NewConversationHandler(db),
+		pages:  pages,
+		events: events.NewLog(rdb, events.FromConfig(cfg.Events)),
+	}
+}
+
+// --- CREATE DIRECT CONVERSATION ---
+// CreateDirectConversation returns the caller's direct conversation with
+// another user, creating it on first use.
+func (c *ConversationController) CreateDirectConversation(ctx context.Context, req *conversationv1.CreateDirectConversationRequest) (*conversationv1.CreateDirectConversationResponse, error) {
+	start := time.Now()
+	const op = "create_direct_conversation"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if target == caller {
+		return nil, status.Error(codes.InvalidArgument, "cannot start a conversation with yourself")
+	}
+
+	if err := c.checkAddable(ctx, caller, []gocql.UUID{target}); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	id, created, err := c.h.ClaimDirect(ctx, caller, target, gocql.TimeUUID())
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	conv, err := c.h.GetConversation(ctx, id)
+	if status.Code(err) == codes.NotFound {
+		// first use, or an earlier creation of the claimed id that did not finish
+		conv, err = c.createDirect(ctx, id, caller, target)
+		created = true
+	}
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if created {
+		c.publishMembership(ctx, id, &realtimev1.MembershipEvent{
+			Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_ADDED,
+			UserIds: []string{caller.String(), target.String()},
+			ActorId: caller.String(),
+		})
+	}
+
+	members, err := c.h.Members(ctx, id)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.CreateDirectConversationResponse{Conversation: conv, Members: members, Created: created}, nil
+}
+
+func (c *ConversationController) createDirect(ctx context.Context, id, caller, target gocql.UUID) (*conversationv1.Conversation, error) {
+	now := timestamppb.New(time.Now().Truncate(time.Millisecond))
+	conv := &conversationv1.Conversation{
+		Id:             id.String(),
+		Type:           conversationv1.ConversationType_CONVERSATION_TYPE_DIRECT,
+		CreatedBy:      caller.String(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		LastActivityAt: now,
+	}
+	// neither side outranks the other in a direct conversation
+	members := []*conversationv1.Member{
+		{UserId: caller.String(), Role: roles.Member, JoinedAt: now},
+		{UserId: target.String(), Role: roles.Member, JoinedAt: now, AddedBy: caller.String()},
+	}
+	if err := c.h.CreateConversation(ctx, conv, members); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// --- CREATE GROUP ---
+func (c *ConversationController) CreateGroup(ctx context.Context, req *conversationv1.CreateGroupRequest) (*conversationv1.CreateGroupResponse, error) {
+	start := time.Now()
+	const op = "create_group"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if n, maxLen := utf8.RuneCountInString(name), c.maxNameLength(); n > maxLen {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters, got %d", maxLen, n)
+	}
+
+	ids, err := parseUserIDs(req.GetMemberIds(), caller)
+	if err != nil {
+		return nil, err
+	}
+	if maxMembers := c.maxGroupMembers(); len(ids)+1 > maxMembers {
+		return nil, status.Errorf(codes.InvalidArgument, "a group may have at most %d members", maxMembers)
+	}
+
+	if err := c.checkAddable(ctx, caller, ids); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	now := timestamppb.New(time.Now().Truncate(time.Millisecond))
+	conv := &conversationv1.Conversation{
+		Id:             gocql.TimeUUID().String(),
+		Type:           conversationv1.ConversationType_CONVERSATION_TYPE_GROUP,
+		Name:           name,
+		CreatedBy:      caller.String(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		LastActivityAt: now,
+	}
+	members := []*conversationv1.Member{{UserId: caller.String(), Role: roles.Owner, JoinedAt: now}}
+	for _, id := range ids {
+		members = append(members, &conversationv1.Member{UserId: id.String(), Role: roles.Member, JoinedAt: now, AddedBy: caller.String()})
+	}
+
+	if err := c.h.CreateConversation(ctx, conv, members); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	memberIDs := make([]string, len(members))
+	for i, m := range members {
+		memberIDs[i] = m.UserId
+	}
+	convID, _ := gocql.ParseUUID(conv.Id)
+	c.publishMembership(ctx, convID, &realtimev1.MembershipEvent{
+		Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_ADDED,
+		UserIds: memberIDs,
+		ActorId: caller.String(),
+	})
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.CreateGroupResponse{Conversation: conv, Members: members}, nil
+}
+
+// --- GET CONVERSATION ---
+func (c *ConversationController) GetConversation(ctx context.Context, req *conversationv1.GetConversationRequest) (*conversationv1.GetConversationResponse, error) {
+	start := time.Now()
+	const op = "get_conversation"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conv, _, err := c.conversationFor(ctx, req.GetId(), caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	id, _ := gocql.ParseUUID(conv.Id)
+	members, err := c.h.Members(ctx, id)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.GetConversationResponse{Conversation: conv, Members: members}, nil
+}
+
+// --- LIST MY CONVERSATIONS ---
+func (c *ConversationController) ListMyConversations(ctx context.Context, req *conversationv1.ListMyConversationsRequest) (*conversationv1.ListMyConversationsResponse, error) {
+	start := time.Now()
+	const op = "list_my_conversations"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, state, err := c.decodePage(ctx, op, req.GetPageLimit(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	convs, next, err := c.h.ListUserConversations(ctx, caller, pageSize, state)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.ListMyConversationsResponse{Conversations: convs, PageToken: c.encodePage(ctx, op, pageSize, next)}, nil
+}
+
+// conversationFor loads conversation id along with caller's membership. A
+// conversation the caller is not in is reported as not found, so its
+// existence is not revealed.
+func (c *ConversationController) conversationFor(ctx context.Context, id string, caller gocql.UUID) (*conversationv1.Conversation, *conversationv1.Member, error) {
+	convID, err := parseUUID("conversation_id", id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, err := c.h.Member(ctx, convID, caller)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil, status.Error(codes.NotFound, "conversation not found")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conv, err := c.h.GetConversation(ctx, convID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conv, member, nil
+}
+
+// publishMembership appends a membership event for conversation convID to
+// the logs of its members and of extra users, such as one just removed.
+// Failures are only logged: the change is stored either way, and clients
+// that miss the event see it the next time they load the conversation.
+func (c *ConversationController) publishMembership(ctx context.Context, convID gocql.UUID, m *realtimev1.MembershipEvent, extra ...string) {
+	members, err := c.h.Members(ctx, convID)
+	if err == nil {
+		ids := extra
+		for _, member := range members {
+			ids = append(ids, member.UserId)
+		}
+		err = c.events.Append(ctx, &realtimev1.Event{
+			ConversationId: convID.String(),
+			OccurredAt:     timestamppb.Now(),
+			Payload:        &realtimev1.Event_Membership{Membership: m},
+		}, ids...)
+	}
+	if err != nil {
+		slog.Warn("failed to publish membership event",
+			slog.String("conversation_id", convID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// checkAddable verifies that every one of ids exists and that none has a
+// block in place with caller.
+func (c *ConversationController) checkAddable(ctx context.Context, caller gocql.UUID, ids []gocql.UUID) error {
+	missing, err := users.Missing(ctx, c.h.Db, ids)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to check users: %v", err)
+	}
+	if len(missing) > 0 {
+		return status.Errorf(codes.NotFound, "user %s not found", missing[0])
+	}
+
+	blocked, err := blocklist.Blocked(ctx, c.h.Db, caller, ids)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to check blocks: %v", err)
+	}
+	for _, id := range ids {
+		if blocked[id] {
+			return status.Errorf(codes.PermissionDenied, "cannot add user %s", id)
+		}
+	}
+	return nil
+}
+
+// parseUserIDs validates a list of user ids, dropping repeats and the caller.
+func parseUserIDs(values []string, caller gocql.UUID) ([]gocql.UUID, error) {
+	seen := make(map[gocql.UUID]bool, len(values))
+	ids := make([]gocql.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := parseUUID("user_ids", v)
+		if err != nil {
+			return nil, err
+		}
+		if id == caller || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func callerUUID(ctx context.Context) (gocql.UUID, error) {
+	claims, ok := authjwt.ClaimsFromContext(ctx)
+	if !ok || claims.UserID == "" {
+		return gocql.UUID{}, status.Error(codes.Unauthenticated, "missing caller identity")
+	}
+	id, err := gocql.ParseUUID(claims.UserID)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.Unauthenticated, "invalid caller id: %v", err)
+	}
+	return id, nil
+}
+
+// parseUUID validates a UUID request field, naming the field on failure.
+func parseUUID(field, value string) (gocql.UUID, error) {
+	if value == "" {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	id, err := gocql.ParseUUID(value)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+	}
+	return id, nil
+}
+
+// decodePage validates a list call's page token for the caller, returning the
+// page size to query with and the driver state to resume from.
+func (c *ConversationController) decodePage(ctx context.Context, scope string, pageLimit uint32, pageToken string) (int32, []byte, error) {
+	page, err := c.pages.Decode(pageScope(ctx, scope), pageToken)
+	if err != nil {
+		return 0, nil, err
+	}
+	return page.Limit(pageLimit, c.maxPageSize()), page.State, nil
+}
+
+// encodePage returns the page token for the page after the one just read.
+func (c *ConversationController) encodePage(ctx context.Context, scope string, pageSize int32, pageState []byte) string {
+	return c.pages.Encode(pageScope(ctx, scope), pageState, pageSize)
+}
+
+func pageScope(ctx context.Context, scope string) string {
+	if claims, ok := authjwt.ClaimsFromContext(ctx); ok {
+		return scope + ":" + claims.UserID
+	}
+	return scope
+}
+
+func (c *ConversationController) maxGroupMembers() int {
+	if n := c.Config.Conversation.MaxGroupMembers; n > 0 {
+		return n
+	}
+	return defaultMaxGroupMembers
+}
+
+func (c *ConversationController) maxNameLength() int {
+	if n := c.Config.Conversation.MaxNameLength; n > 0 {
+		return n
+	}
+	return defaultMaxNameLength
+}
+
+func (c *ConversationController) maxPageSize() int32 {
+	if n := c.Config.Conversation.MaxPageSize; n > 0 {
+		return int32(min(n, math.MaxInt32))
+	}
+	return defaultMaxPageSize
+}
+
+func (c *ConversationController) observeDuration(op, db string, start time.Time) {
+	c.M.Duration.WithLabelValues(op, db).Observe(time.Since(start).Seconds())
+}
+
+func (c *ConversationController) observeError(op, db string) {
+	c.M.Errors.WithLabelValues(op, db).Inc()
+}
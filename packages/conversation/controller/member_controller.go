@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/conversation/roles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- ADD MEMBERS ---
+func (c *ConversationController) AddMembers(ctx context.Context, req *conversationv1.AddMembersRequest) (*conversationv1.AddMembersResponse, error) {
+	start := time.Now()
+	const op = "add_members"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := parseUserIDs(req.GetUserIds(), caller)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one user id is required")
+	}
+
+	conv, member, err := c.groupFor(ctx, req.GetConversationId(), caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if !roles.CanAdd(member.Role) {
+		return nil, status.Error(codes.PermissionDenied, "only admins can add members")
+	}
+
+	convID, _ := gocql.ParseUUID(conv.Id)
+	existing, err := c.h.Members(ctx, convID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	inGroup := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		inGroup[m.UserId] = true
+	}
+	var added []gocql.UUID
+	for _, id := range ids {
+		if !inGroup[id.String()] {
+			added = append(added, id)
+		}
+	}
+	if len(added) == 0 {
+		c.observeDuration(op, "cassandra", start)
+		return &conversationv1.AddMembersResponse{}, nil
+	}
+	// counted before writing, so concurrent adds can overshoot the cap; it
+	// bounds what one call adds rather than the group's exact size
+	if maxMembers := c.maxGroupMembers(); len(existing)+len(added) > maxMembers {
+		return nil, status.Errorf(codes.FailedPrecondition, "a group may have at most %d members", maxMembers)
+	}
+
+	if err := c.checkAddable(ctx, caller, added); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	now := time.Now()
+	members := make([]*conversationv1.Member, len(added))
+	for i, id := range added {
+		members[i] = &conversationv1.Member{UserId: id.String(), Role: roles.Member, JoinedAt: timestamppb.New(now), AddedBy: caller.String()}
+	}
+	if err := c.h.AddMembers(ctx, conv, members); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	// joining counts as activity, which also lists the group for the newcomers
+	if err := c.h.Touch(ctx, convID, now); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	addedIDs := make([]string, len(added))
+	for i, id := range added {
+		addedIDs[i] = id.String()
+	}
+	c.publishMembership(ctx, convID, &realtimev1.MembershipEvent{
+		Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_ADDED,
+		UserIds: addedIDs,
+		ActorId: caller.String(),
+	})
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.AddMembersResponse{Members: members}, nil
+}
+
+// --- REMOVE MEMBER ---
+func (c *ConversationController) RemoveMember(ctx context.Context, req *conversationv1.RemoveMemberRequest) (*conversationv1.RemoveMemberResponse, error) {
+	start := time.Now()
+	const op = "remove_member"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if target == caller {
+		return nil, status.Error(codes.InvalidArgument, "use LeaveConversation to remove yourself")
+	}
+
+	conv, member, err := c.groupFor(ctx, req.GetConversationId(), caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	convID, _ := gocql.ParseUUID(conv.Id)
+	targetMember, err := c.h.Member(ctx, convID, target)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if !roles.CanRemove(member.Role, targetMember.Role) {
+		return nil, status.Error(codes.PermissionDenied, "your role cannot remove this member")
+	}
+
+	if err := c.h.RemoveMember(ctx, conv, target); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	c.publishMembership(ctx, convID, &realtimev1.MembershipEvent{
+		Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_REMOVED,
+		UserIds: []string{target.String()},
+		ActorId: caller.String(),
+	}, target.String())
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.RemoveMemberResponse{}, nil
+}
+
+// --- LEAVE CONVERSATION ---
+// LeaveConversation removes the caller from a group. An owner leaving hands
+// ownership to the successor roles.Successor picks.
+func (c *ConversationController) LeaveConversation(ctx context.Context, req *conversationv1.LeaveConversationRequest) (*conversationv1.LeaveConversationResponse, error) {
+	start := time.Now()
+	const op = "leave_conversation"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conv, member, err := c.groupFor(ctx, req.GetConversationId(), caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	convID, _ := gocql.ParseUUID(conv.Id)
+
+	if member.Role == roles.Owner {
+		members, err := c.h.Members(ctx, convID)
+		if err != nil {
+			c.observeError(op, "cassandra")
+			return nil, err
+		}
+		// the last one out leaves an empty group nobody can see
+		if next := roles.Successor(members, caller.String()); next != nil {
+			nextID, _ := gocql.ParseUUID(next.UserId)
+			if err := c.h.SetRole(ctx, convID, nextID, roles.Owner); err != nil {
+				c.observeError(op, "cassandra")
+				return nil, err
+			}
+			c.publishMembership(ctx, convID, &realtimev1.MembershipEvent{
+				Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_ROLE_CHANGED,
+				UserIds: []string{next.UserId},
+				ActorId: caller.String(),
+				Role:    roles.Owner,
+			})
+		}
+	}
+
+	if err := c.h.RemoveMember(ctx, conv, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	c.publishMembership(ctx, convID, &realtimev1.MembershipEvent{
+		Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_LEFT,
+		UserIds: []string{caller.String()},
+		ActorId: caller.String(),
+	}, caller.String())
+
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.LeaveConversationResponse{}, nil
+}
+
+// --- SET MEMBER ROLE ---
+func (c *ConversationController) SetMemberRole(ctx context.Context, req *conversationv1.SetMemberRoleRequest) (*conversationv1.SetMemberRoleResponse, error) {
+	start := time.Now()
+	const op = "set_member_role"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if target == caller {
+		return nil, status.Error(codes.InvalidArgument, "cannot change your own role")
+	}
+	if !roles.Valid(req.GetRole()) {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	conv, member, err := c.groupFor(ctx, req.GetConversationId(), caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	convID, _ := gocql.ParseUUID(conv.Id)
+	targetMember, err := c.h.Member(ctx, convID, target)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if !roles.CanSetRole(member.Role, targetMember.Role, req.GetRole()) {
+		return nil, status.Error(codes.PermissionDenied, "only the owner can change roles")
+	}
+
+	if req.GetRole() == roles.Owner {
+		err = c.h.TransferOwnership(ctx, convID, caller, target)
+	} else {
+		err = c.h.SetRole(ctx, convID, target, req.GetRole())
+	}
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	roleChanged := func(userID gocql.UUID, role conversationv1.MemberRole) {
+		c.publishMembership(ctx, convID, &realtimev1.MembershipEvent{
+			Change:  realtimev1.MembershipChange_MEMBERSHIP_CHANGE_ROLE_CHANGED,
+			UserIds: []string{userID.String()},
+			ActorId: caller.String(),
+			Role:    role,
+		})
+	}
+	roleChanged(target, req.GetRole())
+	if req.GetRole() == roles.Owner {
+		roleChanged(caller, roles.Admin)
+	}
+
+	targetMember.Role = req.GetRole()
+	c.observeDuration(op, "cassandra", start)
+	return &conversationv1.SetMemberRoleResponse{Member: targetMember}, nil
+}
+
+// groupFor is conversationFor restricted to groups; direct conversations have
+// a fixed pair of members.
+func (c *ConversationController) groupFor(ctx context.Context, id string, caller gocql.UUID) (*conversationv1.Conversation, *conversationv1.Member, error) {
+	conv, member, err := c.conversationFor(ctx, id, caller)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conv.Type != conversationv1.ConversationType_CONVERSATION_TYPE_GROUP {
+		return nil, nil, status.Error(codes.FailedPrecondition, "membership of a direct conversation cannot change")
+	}
+	return conv, member, nil
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// writeConcurrency bounds the per-member writes a single call runs at once.
+const writeConcurrency = 16
+
+type ConversationHandler struct {
+	Db *gocql.Session
+}
+
+func NewConversationHandler(db *gocql.Session) *ConversationHandler {
+	return &ConversationHandler{Db: db}
+}
+
+// --- DB INSERT ---
+// CreateConversation writes conv and its members. Member rows go first and the
+// conversation row last, so a failure part way leaves nothing visible.
+func (h *ConversationHandler) CreateConversation(ctx context.Context, conv *conversationv1.Conversation, members []*conversationv1.Member) error {
+	id, err := gocql.ParseUUID(conv.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	if err := h.writeMembers(ctx, id, conv.LastActivityAt.AsTime(), members); err != nil {
+		return err
+	}
+
+	createdBy, err := gocql.ParseUUID(conv.CreatedBy)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	// timestamped like Touch so the first bump always supersedes it
+	lastActivity := conv.LastActivityAt.AsTime()
+	if err := h.Db.Query(
+		`INSERT INTO chat.conversations (id, type, name, created_by, created_at, updated_at, last_activity_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?) USING TIMESTAMP ?`,
+		id, int(conv.Type), conv.Name, createdBy,
+		conv.CreatedAt.AsTime(), conv.UpdatedAt.AsTime(), lastActivity, lastActivity.UnixMicro(),
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to insert conversation: %v", err)
+	}
+	return nil
+}
+
+// --- DB CLAIM DIRECT ---
+// ClaimDirect returns the direct conversation id of the pair a, b, claiming
+// id for it when the pair has none. claimed reports whether id was taken.
+func (h *ConversationHandler) ClaimDirect(ctx context.Context, a, b, id gocql.UUID) (gocql.UUID, bool, error) {
+	low, high := a, b
+	if bytes.Compare(low.Bytes(), high.Bytes()) > 0 {
+		low, high = high, low
+	}
+
+	existing := map[string]any{}
+	applied, err := h.Db.Query(
+		`INSERT INTO chat.direct_conversations (user_low, user_high, conversation_id) VALUES (?, ?, ?) IF NOT EXISTS`,
+		low, high, id,
+	).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return gocql.UUID{}, false, status.Errorf(codes.Internal, "failed to claim direct conversation: %v", err)
+	}
+	if applied {
+		return id, true, nil
+	}
+
+	claimedID, ok := existing["conversation_id"].(gocql.UUID)
+	if !ok {
+		return gocql.UUID{}, false, status.Error(codes.Internal, "direct conversation claim has no id")
+	}
+	return claimedID, false, nil
+}
+
+// --- DB SELECT ---
+func (h *ConversationHandler) GetConversation(ctx context.Context, id gocql.UUID) (*conversationv1.Conversation, error) {
+	var row conversationRow
+	err := h.Db.Query(
+		`SELECT `+conversationColumns+` FROM chat.conversations WHERE id = ?`, id,
+	).WithContext(ctx).Consistency(gocql.One).Scan(row.dest()...)
+	if err == gocql.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "conversation not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query conversation: %v", err)
+	}
+	return row.toProto(), nil
+}
+
+// --- DB LIST USER CONVERSATIONS ---
+// ListUserConversations returns a page of userID's conversations, most
+// recently active first. Index rows that no longer match their conversation,
+// left behind by activity bumps or removals, are skipped and cleaned up.
+func (h *ConversationHandler) ListUserConversations(ctx context.Context, userID gocql.UUID, pageLimit int32, pageToken []byte) ([]*conversationv1.Conversation, []byte, error) {
+	iter := h.pagedQuery(ctx,
+		`SELECT last_activity_at, conversation_id FROM chat.user_conversations WHERE user_id = ?`,
+		pageLimit, pageToken, userID,
+	).Iter()
+
+	type indexRow struct {
+		at time.Time
+		id gocql.UUID
+	}
+	var (
+		rows []indexRow
+		row  indexRow
+	)
+	for iter.Scan(&row.at, &row.id) {
+		rows = append(rows, row)
+	}
+	nextPage := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to list conversations: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, nextPage, nil
+	}
+
+	ids := make([]gocql.UUID, len(rows))
+	for i, r := range rows {
+		ids[i] = r.id
+	}
+
+	var (
+		convs    map[gocql.UUID]*conversationv1.Conversation
+		isMember map[gocql.UUID]bool
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		convs, err = h.conversations(gctx, ids)
+		return err
+	})
+	g.Go(func() (err error) {
+		isMember, err = h.memberOf(gctx, ids, userID)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	var out []*conversationv1.Conversation
+	for _, r := range rows {
+		conv, ok := convs[r.id]
+		if !ok {
+			// still being created
+			continue
+		}
+		current := conv.LastActivityAt.AsTime()
+		switch {
+		case !isMember[r.id] || r.at.Before(current):
+			h.dropIndexRow(ctx, userID, r.at, r.id)
+		case r.at.Equal(current):
+			out = append(out, conv)
+		}
+		// a row ahead of the conversation belongs to a bump still in flight
+	}
+	return out, nextPage, nil
+}
+
+// --- DB TOUCH ---
+// Touch moves conversation id to at in every member's list. Activity only
+// moves forward: the conversation row is written with at as its timestamp,
+// so concurrent bumps settle on the latest, and the index rows they leave
+// behind are dropped when listed.
+func (h *ConversationHandler) Touch(ctx context.Context, id gocql.UUID, at time.Time) error {
+	conv, err := h.GetConversation(ctx, id)
+	if err != nil {
+		return err
+	}
+	prev := conv.LastActivityAt.AsTime()
+	// Cassandra keeps milliseconds
+	at = at.Truncate(time.Millisecond)
+	if !at.After(prev) {
+		return nil
+	}
+
+	members, err := h.Members(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// new index rows first, so the conversation is never missing from a list
+	if err := h.forEachMember(ctx, members, func(ctx context.Context, userID gocql.UUID) error {
+		return h.Db.Query(
+			`INSERT INTO chat.user_conversations (user_id, last_activity_at, conversation_id) VALUES (?, ?, ?)`,
+			userID, at, id,
+		).WithContext(ctx).Exec()
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to index conversation activity: %v", err)
+	}
+
+	if err := h.Db.Query(
+		`UPDATE chat.conversations USING TIMESTAMP ? SET last_activity_at = ? WHERE id = ?`,
+		at.UnixMicro(), at, id,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to update conversation activity: %v", err)
+	}
+
+	if err := h.forEachMember(ctx, members, func(ctx context.Context, userID gocql.UUID) error {
+		return h.deleteIndexRow(ctx, userID, prev, id)
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to drop stale conversation activity: %v", err)
+	}
+	return nil
+}
+
+// conversations fetches the conversations among ids that exist.
+func (h *ConversationHandler) conversations(ctx context.Context, ids []gocql.UUID) (map[gocql.UUID]*conversationv1.Conversation, error) {
+	iter := h.Db.Query(
+		`SELECT `+conversationColumns+` FROM chat.conversations WHERE id IN ?`, ids,
+	).WithContext(ctx).Consistency(gocql.One).Iter()
+
+	out := make(map[gocql.UUID]*conversationv1.Conversation, len(ids))
+	var row conversationRow
+	for iter.Scan(row.dest()...) {
+		out[row.id] = row.toProto()
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query conversations: %v", err)
+	}
+	return out, nil
+}
+
+func (h *ConversationHandler) dropIndexRow(ctx context.Context, userID gocql.UUID, at time.Time, id gocql.UUID) {
+	// best effort; the row is skipped again on the next listing if this fails
+	_ = h.deleteIndexRow(ctx, userID, at, id)
+}
+
+func (h *ConversationHandler) deleteIndexRow(ctx context.Context, userID gocql.UUID, at time.Time, id gocql.UUID) error {
+	return h.Db.Query(
+		`DELETE FROM chat.user_conversations WHERE user_id = ? AND last_activity_at = ? AND conversation_id = ?`,
+		userID, at, id,
+	).WithContext(ctx).Exec()
+}
+
+// forEachMember runs fn for each member with bounded concurrency.
+func (h *ConversationHandler) forEachMember(ctx context.Context, members []*conversationv1.Member, fn func(context.Context, gocql.UUID) error) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(writeConcurrency)
+	for _, m := range members {
+		userID, err := gocql.ParseUUID(m.UserId)
+		if err != nil {
+			return err
+		}
+		g.Go(func() error { return fn(gctx, userID) })
+	}
+	return g.Wait()
+}
+
+// pagedQuery returns a query fetching a single page of pageLimit rows starting
+// at pageToken. Setting PageState, even to nil, stops gocql from transparently
+// fetching the following pages while iterating.
+func (h *ConversationHandler) pagedQuery(ctx context.Context, stmt string, pageLimit int32, pageToken []byte, values ...any) *gocql.Query {
+	pageSize := int(pageLimit)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	return h.Db.Query(stmt, values...).WithContext(ctx).PageSize(pageSize).PageState(pageToken)
+}
+
+// conversationColumns are the chat.conversations columns scanned into a
+// conversationRow, in order.
+const conversationColumns = `id, type, name, created_by, created_at, updated_at, last_activity_at`
+
+type conversationRow struct {
+	id             gocql.UUID
+	kind           int
+	name           string
+	createdBy      gocql.UUID
+	createdAt      time.Time
+	updatedAt      time.Time
+	lastActivityAt time.Time
+}
+
+func (r *conversationRow) dest() []any {
+	return []any{&r.id, &r.kind, &r.name, &r.createdBy, &r.createdAt, &r.updatedAt, &r.lastActivityAt}
+}
+
+func (r *conversationRow) toProto() *conversationv1.Conversation {
+	return &conversationv1.Conversation{
+		Id:             r.id.String(),
+		Type:           conversationv1.ConversationType(r.kind),
+		Name:           r.name,
+		CreatedBy:      r.createdBy.String(),
+		CreatedAt:      timestamppb.New(r.createdAt),
+		UpdatedAt:      timestamppb.New(r.updatedAt),
+		LastActivityAt: timestamppb.New(r.lastActivityAt),
+	}
+}
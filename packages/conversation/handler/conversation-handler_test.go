@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/require"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	"github.com/yaninyzwitty/chat/packages/conversation/handler"
+	"github.com/yaninyzwitty/chat/packages/conversation/roles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newGroup(t *testing.T, h *handler.ConversationHandler, at time.Time, owner gocql.UUID, others ...gocql.UUID) *conversationv1.Conversation {
+	t.Helper()
+	ts := timestamppb.New(at.Truncate(time.Millisecond))
+	conv := &conversationv1.Conversation{
+		Id:             gocql.TimeUUID().String(),
+		Type:           conversationv1.ConversationType_CONVERSATION_TYPE_GROUP,
+		Name:           "group",
+		CreatedBy:      owner.String(),
+		CreatedAt:      ts,
+		UpdatedAt:      ts,
+		LastActivityAt: ts,
+	}
+	members := []*conversationv1.Member{{UserId: owner.String(), Role: roles.Owner, JoinedAt: ts}}
+	for _, id := range others {
+		members = append(members, &conversationv1.Member{UserId: id.String(), Role: roles.Member, JoinedAt: ts, AddedBy: owner.String()})
+	}
+	require.NoError(t, h.CreateConversation(context.Background(), conv, members))
+	return conv
+}
+
+func listIDs(t *testing.T, h *handler.ConversationHandler, userID gocql.UUID) []string {
+	t.Helper()
+	convs, _, err := h.ListUserConversations(context.Background(), userID, 50, nil)
+	require.NoError(t, err)
+	ids := make([]string, len(convs))
+	for i, c := range convs {
+		ids[i] = c.Id
+	}
+	return ids
+}
+
+func TestListUserConversationsByActivity(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewConversationHandler(db)
+
+	alice, bob := gocql.TimeUUID(), gocql.TimeUUID()
+	now := time.Now()
+	older := newGroup(t, h, now.Add(-time.Hour), alice, bob)
+	newer := newGroup(t, h, now.Add(-time.Minute), alice)
+
+	require.Equal(t, []string{newer.Id, older.Id}, listIDs(t, h, alice))
+
+	// bumping the older group moves it to the top exactly once
+	olderID, _ := gocql.ParseUUID(older.Id)
+	require.NoError(t, h.Touch(ctx, olderID, now))
+	require.Equal(t, []string{older.Id, newer.Id}, listIDs(t, h, alice))
+	require.Equal(t, []string{older.Id}, listIDs(t, h, bob))
+
+	// activity never moves backwards
+	require.NoError(t, h.Touch(ctx, olderID, now.Add(-time.Hour)))
+	conv, err := h.GetConversation(ctx, olderID)
+	require.NoError(t, err)
+	require.Equal(t, now.Truncate(time.Millisecond), conv.LastActivityAt.AsTime().Local())
+
+	// a removed member no longer lists the group
+	updated, err := h.GetConversation(ctx, olderID)
+	require.NoError(t, err)
+	require.NoError(t, h.RemoveMember(ctx, updated, bob))
+	require.Empty(t, listIDs(t, h, bob))
+
+	_, err = h.Member(ctx, olderID, bob)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestClaimDirect(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewConversationHandler(db)
+
+	alice, bob := gocql.TimeUUID(), gocql.TimeUUID()
+	first := gocql.TimeUUID()
+
+	id, claimed, err := h.ClaimDirect(ctx, alice, bob, first)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.Equal(t, first, id)
+
+	// either side asking again gets the same conversation
+	id, claimed, err = h.ClaimDirect(ctx, bob, alice, gocql.TimeUUID())
+	require.NoError(t, err)
+	require.False(t, claimed)
+	require.Equal(t, first, id)
+}
+
+func TestTransferOwnership(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewConversationHandler(db)
+
+	alice, bob := gocql.TimeUUID(), gocql.TimeUUID()
+	conv := newGroup(t, h, time.Now(), alice, bob)
+	convID, _ := gocql.ParseUUID(conv.Id)
+
+	require.NoError(t, h.TransferOwnership(ctx, convID, alice, bob))
+
+	members, err := h.Members(ctx, convID)
+	require.NoError(t, err)
+	got := map[string]conversationv1.MemberRole{}
+	for _, m := range members {
+		got[m.UserId] = m.Role
+	}
+	require.Equal(t, roles.Admin, got[alice.String()])
+	require.Equal(t, roles.Owner, got[bob.String()])
+}
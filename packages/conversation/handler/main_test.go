@@ -0,0 +1,49 @@
+package handler_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/cassandra"
+	database "github.com/yaninyzwitty/chat/packages/db"
+)
+
+var connectionHost = ""
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	cassandraContainer, err := cassandra.Run(ctx,
+		"cassandra:4.1.3",
+		cassandra.WithInitScripts(filepath.Join("testdata", "init.cql")),
+	)
+
+	if err != nil {
+		slog.Error("failed to load container", "error", err)
+		os.Exit(1)
+	}
+
+	defer func() {
+		if err := testcontainers.TerminateContainer(cassandraContainer); err != nil {
+			slog.Error("failed to terminate container", "error", err)
+		}
+	}()
+
+	connectionHost, err = cassandraContainer.ConnectionHost(ctx)
+	if err != nil {
+		slog.Error("failed to get connection host", "error", err)
+		os.Exit(1)
+	}
+
+	res := m.Run()
+	os.Exit(res)
+}
+
+func getConn() (*gocql.Session, error) {
+	return database.ConnectLocal(connectionHost)
+}
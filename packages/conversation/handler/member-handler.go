@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	"github.com/yaninyzwitty/chat/packages/conversation/roles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- DB SELECT MEMBERS ---
+func (h *ConversationHandler) Members(ctx context.Context, id gocql.UUID) ([]*conversationv1.Member, error) {
+	iter := h.Db.Query(
+		`SELECT user_id, role, joined_at, added_by FROM chat.conversation_members WHERE conversation_id = ?`, id,
+	).WithContext(ctx).Iter()
+
+	var (
+		members []*conversationv1.Member
+		row     memberRow
+	)
+	for iter.Scan(row.dest()...) {
+		members = append(members, row.toProto())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list members: %v", err)
+	}
+	return members, nil
+}
+
+// --- DB SELECT MEMBER ---
+// Member returns userID's membership of conversation id, or NotFound.
+func (h *ConversationHandler) Member(ctx context.Context, id, userID gocql.UUID) (*conversationv1.Member, error) {
+	var row memberRow
+	err := h.Db.Query(
+		`SELECT user_id, role, joined_at, added_by FROM chat.conversation_members WHERE conversation_id = ? AND user_id = ?`,
+		id, userID,
+	).WithContext(ctx).Consistency(gocql.One).Scan(row.dest()...)
+	if err == gocql.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "not a member of this conversation")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query member: %v", err)
+	}
+	return row.toProto(), nil
+}
+
+// --- DB INSERT MEMBERS ---
+// AddMembers adds members to conv, listing it for them at its current
+// activity; callers bump the activity afterwards.
+func (h *ConversationHandler) AddMembers(ctx context.Context, conv *conversationv1.Conversation, members []*conversationv1.Member) error {
+	id, err := gocql.ParseUUID(conv.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	return h.writeMembers(ctx, id, conv.LastActivityAt.AsTime(), members)
+}
+
+// --- DB DELETE MEMBER ---
+func (h *ConversationHandler) RemoveMember(ctx context.Context, conv *conversationv1.Conversation, userID gocql.UUID) error {
+	id, err := gocql.ParseUUID(conv.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`DELETE FROM chat.conversation_members WHERE conversation_id = ? AND user_id = ?`, id, userID)
+	// index rows from bumps racing this one are dropped once listed
+	batch.Query(`DELETE FROM chat.user_conversations WHERE user_id = ? AND last_activity_at = ? AND conversation_id = ?`,
+		userID, conv.LastActivityAt.AsTime(), id)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to remove member: %v", err)
+	}
+	return nil
+}
+
+// --- DB UPDATE ROLE ---
+func (h *ConversationHandler) SetRole(ctx context.Context, id, userID gocql.UUID, role conversationv1.MemberRole) error {
+	applied, err := h.Db.Query(
+		`UPDATE chat.conversation_members SET role = ? WHERE conversation_id = ? AND user_id = ? IF EXISTS`,
+		int(role), id, userID,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to update role: %v", err)
+	}
+	if !applied {
+		return status.Error(codes.NotFound, "not a member of this conversation")
+	}
+	return nil
+}
+
+// --- DB TRANSFER OWNERSHIP ---
+// TransferOwnership makes to the owner of conversation id and from an admin,
+// in one single-partition batch.
+func (h *ConversationHandler) TransferOwnership(ctx context.Context, id, from, to gocql.UUID) error {
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`UPDATE chat.conversation_members SET role = ? WHERE conversation_id = ? AND user_id = ?`, int(roles.Owner), id, to)
+	batch.Query(`UPDATE chat.conversation_members SET role = ? WHERE conversation_id = ? AND user_id = ?`, int(roles.Admin), id, from)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to transfer ownership: %v", err)
+	}
+	return nil
+}
+
+// memberOf reports which of the conversations ids userID belongs to.
+func (h *ConversationHandler) memberOf(ctx context.Context, ids []gocql.UUID, userID gocql.UUID) (map[gocql.UUID]bool, error) {
+	iter := h.Db.Query(
+		`SELECT conversation_id FROM chat.conversation_members WHERE conversation_id IN ? AND user_id = ?`,
+		ids, userID,
+	).WithContext(ctx).Consistency(gocql.One).Iter()
+
+	out := make(map[gocql.UUID]bool, len(ids))
+	var id gocql.UUID
+	for iter.Scan(&id) {
+		out[id] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query memberships: %v", err)
+	}
+	return out, nil
+}
+
+// writeMembers writes the member rows and then the index rows listing
+// conversation id at lastActivity, so a listed conversation always has its
+// membership in place.
+func (h *ConversationHandler) writeMembers(ctx context.Context, id gocql.UUID, lastActivity time.Time, members []*conversationv1.Member) error {
+	byID := make(map[gocql.UUID]*conversationv1.Member, len(members))
+	for _, m := range members {
+		userID, err := gocql.ParseUUID(m.UserId)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid UUID %q: %v", m.UserId, err)
+		}
+		byID[userID] = m
+	}
+
+	if err := h.forEachMember(ctx, members, func(ctx context.Context, userID gocql.UUID) error {
+		m := byID[userID]
+		var addedBy any
+		if m.AddedBy != "" {
+			parsed, err := gocql.ParseUUID(m.AddedBy)
+			if err != nil {
+				return err
+			}
+			addedBy = parsed
+		}
+		return h.Db.Query(
+			`INSERT INTO chat.conversation_members (conversation_id, user_id, role, joined_at, added_by) VALUES (?, ?, ?, ?, ?)`,
+			id, userID, int(m.Role), m.JoinedAt.AsTime(), addedBy,
+		).WithContext(ctx).Exec()
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to insert members: %v", err)
+	}
+
+	if err := h.forEachMember(ctx, members, func(ctx context.Context, userID gocql.UUID) error {
+		return h.Db.Query(
+			`INSERT INTO chat.user_conversations (user_id, last_activity_at, conversation_id) VALUES (?, ?, ?)`,
+			userID, lastActivity, id,
+		).WithContext(ctx).Exec()
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to index conversation: %v", err)
+	}
+	return nil
+}
+
+type memberRow struct {
+	userID   gocql.UUID
+	role     int
+	joinedAt time.Time
+	addedBy  gocql.UUID
+}
+
+func (r *memberRow) dest() []any {
+	return []any{&r.userID, &r.role, &r.joinedAt, &r.addedBy}
+}
+
+func (r *memberRow) toProto() *conversationv1.Member {
+	m := &conversationv1.Member{
+		UserId:   r.userID.String(),
+		Role:     conversationv1.MemberRole(r.role),
+		JoinedAt: timestamppb.New(r.joinedAt),
+	}
+	if r.addedBy != (gocql.UUID{}) {
+		m.AddedBy = r.addedBy.String()
+	}
+	return m
+}
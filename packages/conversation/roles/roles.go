@@ -0,0 +1,67 @@
+// Package roles decides what each member of a group may do to the others.
+// MemberRole values are ordered, so a higher role includes every permission
+// of the roles below it.
+package roles
+
+import (
+	"slices"
+
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+)
+
+const (
+	Member = conversationv1.MemberRole_MEMBER_ROLE_MEMBER
+	Admin  = conversationv1.MemberRole_MEMBER_ROLE_ADMIN
+	Owner  = conversationv1.MemberRole_MEMBER_ROLE_OWNER
+)
+
+// Valid reports whether r is a role a member can hold.
+func Valid(r conversationv1.MemberRole) bool {
+	return r == Member || r == Admin || r == Owner
+}
+
+// CanAdd reports whether actor may add members.
+func CanAdd(actor conversationv1.MemberRole) bool {
+	return actor >= Admin
+}
+
+// CanRemove reports whether actor may remove a member holding target. Nobody
+// removes the owner; they leave instead.
+func CanRemove(actor, target conversationv1.MemberRole) bool {
+	return target != Owner && actor > target
+}
+
+// CanSetRole reports whether actor may change a member holding target to to.
+// Only the owner changes roles, and handing over Owner transfers ownership.
+func CanSetRole(actor, target, to conversationv1.MemberRole) bool {
+	return actor == Owner && target != Owner && Valid(to)
+}
+
+// CanDeleteMessage reports whether actor may delete messages other members
+// sent; everyone may delete their own.
+func CanDeleteMessage(actor conversationv1.MemberRole) bool {
+	return actor >= Admin
+}
+
+// Successor picks who inherits ownership when the owner leaves: the longest
+// standing admin, otherwise the longest standing member. It returns nil when
+// nobody else is left.
+func Successor(members []*conversationv1.Member, leaving string) *conversationv1.Member {
+	var rest []*conversationv1.Member
+	for _, m := range members {
+		if m.GetUserId() != leaving {
+			rest = append(rest, m)
+		}
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+
+	return slices.MinFunc(rest, func(a, b *conversationv1.Member) int {
+		// higher roles first, then earlier joins
+		if a.GetRole() != b.GetRole() {
+			return int(b.GetRole()) - int(a.GetRole())
+		}
+		return a.GetJoinedAt().AsTime().Compare(b.GetJoinedAt().AsTime())
+	})
+}
@@ -0,0 +1,53 @@
+package roles_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	"github.com/yaninyzwitty/chat/packages/conversation/roles"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestPermissions(t *testing.T) {
+	require.True(t, roles.CanAdd(roles.Admin))
+	require.False(t, roles.CanAdd(roles.Member))
+
+	require.True(t, roles.CanRemove(roles.Owner, roles.Admin))
+	require.True(t, roles.CanRemove(roles.Admin, roles.Member))
+	require.False(t, roles.CanRemove(roles.Admin, roles.Admin))
+	require.False(t, roles.CanRemove(roles.Owner, roles.Owner))
+
+	require.True(t, roles.CanSetRole(roles.Owner, roles.Member, roles.Admin))
+	require.True(t, roles.CanSetRole(roles.Owner, roles.Admin, roles.Owner))
+	require.False(t, roles.CanSetRole(roles.Admin, roles.Member, roles.Admin))
+	require.False(t, roles.CanSetRole(roles.Owner, roles.Member, conversationv1.MemberRole_MEMBER_ROLE_UNSPECIFIED))
+
+	require.True(t, roles.CanDeleteMessage(roles.Admin))
+	require.False(t, roles.CanDeleteMessage(roles.Member))
+}
+
+func TestSuccessor(t *testing.T) {
+	now := time.Now()
+	member := func(id string, role conversationv1.MemberRole, joined time.Duration) *conversationv1.Member {
+		return &conversationv1.Member{UserId: id, Role: role, JoinedAt: timestamppb.New(now.Add(joined))}
+	}
+
+	members := []*conversationv1.Member{
+		member("owner", roles.Owner, 0),
+		member("early-member", roles.Member, time.Minute),
+		member("late-admin", roles.Admin, time.Hour),
+		member("early-admin", roles.Admin, 2*time.Minute),
+	}
+	require.Equal(t, "early-admin", roles.Successor(members, "owner").GetUserId())
+
+	members = []*conversationv1.Member{
+		member("owner", roles.Owner, 0),
+		member("late-member", roles.Member, time.Hour),
+		member("early-member", roles.Member, time.Minute),
+	}
+	require.Equal(t, "early-member", roles.Successor(members, "owner").GetUserId())
+
+	require.Nil(t, roles.Successor(members[:1], "owner"))
+}
@@ -59,8 +59,192 @@ func ConnectLocal(host string) (*gocql.Session, error) {
 			created_at TIMESTAMP,
 			updated_at TIMESTAMP,
 			email TEXT,
-			password TEXT
+			password TEXT,
+			bio TEXT,
+			status_text TEXT,
+			status_emoji TEXT,
+			status_expires_at TIMESTAMP,
+			timezone TEXT,
+			locale TEXT,
+			avatar_url TEXT,
+			last_seen_at TIMESTAMP,
+			roles SET<TEXT>,
+			suspended_at TIMESTAMP,
+			suspended_until TIMESTAMP,
+			suspension_reason TEXT,
+			suspended_by UUID
 		)`,
+		`CREATE TABLE IF NOT EXISTS chat.contacts (
+			user_id UUID,
+			contact_id UUID,
+			created_at TIMESTAMP,
+			PRIMARY KEY (user_id, contact_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.contact_requests (
+			user_id UUID,
+			requester_id UUID,
+			message TEXT,
+			created_at TIMESTAMP,
+			PRIMARY KEY (user_id, requester_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.sent_contact_requests (
+			user_id UUID,
+			recipient_id UUID,
+			message TEXT,
+			created_at TIMESTAMP,
+			PRIMARY KEY (user_id, recipient_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_blocks (
+			user_id UUID,
+			blocked_id UUID,
+			created_at TIMESTAMP,
+			PRIMARY KEY (user_id, blocked_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_blocked_by (
+			user_id UUID,
+			blocker_id UUID,
+			created_at TIMESTAMP,
+			PRIMARY KEY (user_id, blocker_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_mutes (
+			user_id UUID,
+			muted_id UUID,
+			created_at TIMESTAMP,
+			PRIMARY KEY (user_id, muted_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_settings (
+			user_id UUID,
+			theme INT,
+			locale TEXT,
+			push_enabled BOOLEAN,
+			email_enabled BOOLEAN,
+			sound_enabled BOOLEAN,
+			show_previews BOOLEAN,
+			hide_email BOOLEAN,
+			hide_last_seen BOOLEAN,
+			preferences MAP<TEXT, TEXT>,
+			updated_at TIMESTAMP,
+			PRIMARY KEY (user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_changes (
+			bucket TEXT,
+			change_id TIMEUUID,
+			user_id UUID,
+			kind TEXT,
+			fields SET<TEXT>,
+			PRIMARY KEY ((bucket), change_id)
+		) WITH CLUSTERING ORDER BY (change_id ASC) AND default_time_to_live = 604800`,
+		`CREATE TABLE IF NOT EXISTS chat.conversations (
+			id TIMEUUID,
+			type INT,
+			name TEXT,
+			created_by UUID,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			last_activity_at TIMESTAMP,
+			PRIMARY KEY (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.conversation_members (
+			conversation_id TIMEUUID,
+			user_id UUID,
+			role INT,
+			joined_at TIMESTAMP,
+			added_by UUID,
+			PRIMARY KEY (conversation_id, user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_conversations (
+			user_id UUID,
+			last_activity_at TIMESTAMP,
+			conversation_id TIMEUUID,
+			PRIMARY KEY ((user_id), last_activity_at, conversation_id)
+		) WITH CLUSTERING ORDER BY (last_activity_at DESC, conversation_id ASC)`,
+		`CREATE TABLE IF NOT EXISTS chat.direct_conversations (
+			user_low UUID,
+			user_high UUID,
+			conversation_id TIMEUUID,
+			PRIMARY KEY ((user_low, user_high))
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.messages (
+			conversation_id TIMEUUID,
+			bucket TEXT,
+			message_id TIMEUUID,
+			sender_id UUID,
+			body TEXT,
+			edited_at TIMESTAMP,
+			deleted_at TIMESTAMP,
+			deleted_by UUID,
+			thread_id TIMEUUID,
+			reply_count INT,
+			last_reply_id TIMEUUID,
+			PRIMARY KEY ((conversation_id, bucket), message_id)
+		) WITH CLUSTERING ORDER BY (message_id DESC)`,
+		`CREATE TABLE IF NOT EXISTS chat.message_buckets (
+			conversation_id TIMEUUID,
+			bucket TEXT,
+			PRIMARY KEY ((conversation_id), bucket)
+		) WITH CLUSTERING ORDER BY (bucket DESC)`,
+		`CREATE TABLE IF NOT EXISTS chat.message_receipts (
+			conversation_id UUID,
+			user_id UUID,
+			read_message_id TIMEUUID,
+			read_at TIMESTAMP,
+			delivered_message_id TIMEUUID,
+			delivered_at TIMESTAMP,
+			PRIMARY KEY ((conversation_id), user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.message_revisions (
+			message_id TIMEUUID,
+			revision_id TIMEUUID,
+			conversation_id TIMEUUID,
+			editor_id UUID,
+			body TEXT,
+			PRIMARY KEY ((message_id), revision_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.message_reactions (
+			message_id TIMEUUID,
+			user_id UUID,
+			emoji TEXT,
+			reacted_at TIMESTAMP,
+			PRIMARY KEY ((message_id), user_id, emoji)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.message_reaction_counts (
+			message_id TIMEUUID,
+			emoji TEXT,
+			count COUNTER,
+			PRIMARY KEY ((message_id), emoji)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.user_reactions (
+			user_id UUID,
+			message_id TIMEUUID,
+			emoji TEXT,
+			reacted_at TIMESTAMP,
+			PRIMARY KEY ((user_id), message_id, emoji)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.thread_replies (
+			thread_id TIMEUUID,
+			message_id TIMEUUID,
+			PRIMARY KEY ((thread_id), message_id)
+		) WITH CLUSTERING ORDER BY (message_id DESC)`,
+		`CREATE TABLE IF NOT EXISTS chat.thread_followers (
+			thread_id TIMEUUID,
+			user_id UUID,
+			PRIMARY KEY ((thread_id), user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.followed_threads (
+			user_id UUID,
+			thread_id TIMEUUID,
+			conversation_id TIMEUUID,
+			followed_at TIMESTAMP,
+			last_reply_id TIMEUUID,
+			read_reply_id TIMEUUID,
+			PRIMARY KEY ((user_id), thread_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat.followed_threads_by_activity (
+			user_id UUID,
+			last_active TIMESTAMP,
+			thread_id TIMEUUID,
+			PRIMARY KEY ((user_id), last_active, thread_id)
+		) WITH CLUSTERING ORDER BY (last_active DESC, thread_id DESC)`,
 	}
 
 	for _, query := range queries {
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/conversation/roles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultEditWindow = 15 * time.Minute
+
+// --- EDIT MESSAGE ---
+func (c *MessageController) EditMessage(ctx context.Context, req *messagev1.EditMessageRequest) (*messagev1.EditMessageResponse, error) {
+	start := time.Now()
+	const op = "edit_message"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := parseCursor("message_id", req.GetMessageId())
+	if err != nil {
+		return nil, err
+	}
+	body := req.GetBody()
+	if err := c.validateBody(body); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	msg, err := c.h.GetMessage(ctx, convID, msgID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if msg.Deleted {
+		return nil, status.Error(codes.FailedPrecondition, "message has been deleted")
+	}
+	if msg.SenderId != caller.String() {
+		return nil, status.Error(codes.PermissionDenied, "only the author may edit a message")
+	}
+	if time.Since(msgID.Time()) > c.editWindow() {
+		return nil, status.Error(codes.FailedPrecondition, "message is too old to edit")
+	}
+	if msg.Body == body {
+		c.observeDuration(op, "cassandra", start)
+		return &messagev1.EditMessageResponse{Message: msg}, nil
+	}
+
+	edited, err := c.h.EditMessage(ctx, msg, body, caller, time.Now())
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.publish(ctx, convID, &realtimev1.Event{
+		ConversationId: edited.ConversationId,
+		OccurredAt:     edited.EditedAt,
+		Payload:        &realtimev1.Event_MessageEdited{MessageEdited: edited},
+	})
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.EditMessageResponse{Message: edited}, nil
+}
+
+// --- DELETE MESSAGE ---
+func (c *MessageController) DeleteMessage(ctx context.Context, req *messagev1.DeleteMessageRequest) (*messagev1.DeleteMessageResponse, error) {
+	start := time.Now()
+	const op = "delete_message"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := parseCursor("message_id", req.GetMessageId())
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := c.conversations.Member(ctx, convID, caller)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Error(codes.NotFound, "conversation not found")
+	}
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	msg, err := c.h.GetMessage(ctx, convID, msgID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if msg.SenderId != caller.String() && !roles.CanDeleteMessage(member.GetRole()) {
+		return nil, status.Error(codes.PermissionDenied, "only the author or an admin may delete a message")
+	}
+	if msg.Deleted {
+		// deleting twice leaves the same tombstone
+		c.observeDuration(op, "cassandra", start)
+		return &messagev1.DeleteMessageResponse{Message: msg}, nil
+	}
+
+	tombstone, deleted, err := c.h.DeleteMessage(ctx, msg, caller, time.Now())
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	// when a concurrent delete got there first, it does the rest
+	if deleted {
+		if tombstone.ThreadId != "" {
+			c.uncountReply(ctx, convID, tombstone)
+		}
+		members := c.publish(ctx, convID, &realtimev1.Event{
+			ConversationId: tombstone.ConversationId,
+			OccurredAt:     tombstone.DeletedAt,
+			Payload:        &realtimev1.Event_MessageDeleted{MessageDeleted: tombstone},
+		})
+		if tombstone.ThreadId == "" {
+			c.recountUnread(ctx, tombstone, members)
+		}
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.DeleteMessageResponse{Message: tombstone}, nil
+}
+
+// --- LIST MESSAGE REVISIONS ---
+func (c *MessageController) ListMessageRevisions(ctx context.Context, req *messagev1.ListMessageRevisionsRequest) (*messagev1.ListMessageRevisionsResponse, error) {
+	start := time.Now()
+	const op = "list_message_revisions"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := parseCursor("message_id", req.GetMessageId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	// revisions are keyed by message alone, so make sure the message is in
+	// the conversation the caller belongs to
+	msg, err := c.h.GetMessage(ctx, convID, msgID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	var revisions []*messagev1.MessageRevision
+	if !msg.Deleted {
+		if revisions, err = c.h.ListRevisions(ctx, msgID); err != nil {
+			c.observeError(op, "cassandra")
+			return nil, err
+		}
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.ListMessageRevisionsResponse{Revisions: revisions}, nil
+}
+
+// editWindow is how long after sending the author may edit a message.
+func (c *MessageController) editWindow() time.Duration {
+	if n := c.Config.Message.EditWindow; n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return defaultEditWindow
+}
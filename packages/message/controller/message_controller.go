@@ -0,0 +1,340 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gocql/gocql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	conversationhandler "github.com/yaninyzwitty/chat/packages/conversation/handler"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+	"github.com/yaninyzwitty/chat/packages/message/unread"
+	"github.com/yaninyzwitty/chat/packages/shared/blocklist"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/shared/pubsub"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultMaxBodyLength   = 4000
+	defaultMessagePageSize = 50
+	defaultMaxPageSize     = 200
+)
+
+type MessageController struct {
+	messagev1.UnimplementedMessageServiceServer
+	h             *handler.MessageHandler
+	conversations *conversationhandler.ConversationHandler
+	events        *events.Log
+	unread        *unread.Store
+	broker        pubsub.Broker
+	M             *monitoring.Metrics
+	Config        *config.Config
+}
+
+func NewMessageController(cfg *config.Config, reg *prometheus.Registry, db *gocql.Session, rdb *redis.Client) *MessageController {
+	return &MessageController{
+		Config:        cfg,
+		M:             monitoring.NewMetrics(reg),
+		h:             handler.NewMessageHandler(db),
+		conversations: conversationhandler.NewConversationHandler(db),
+		events:        events.NewLog(rdb, events.FromConfig(cfg.Events)),
+		unread:        unread.NewStore(rdb),
+		broker:        pubsub.NewRedisBroker(rdb, pubsub.RedisConfig{}),
+	}
+}
+
+// --- SEND MESSAGE ---
+func (c *MessageController) SendMessage(ctx context.Context, req *messagev1.SendMessageRequest) (*messagev1.SendMessageResponse, error) {
+	start := time.Now()
+	const op = "send_message"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	body := req.GetBody()
+	if err := c.validateBody(body); err != nil {
+		return nil, err
+	}
+	var threadID gocql.UUID
+	if req.GetThreadId() != "" {
+		if threadID, err = parseCursor("thread_id", req.GetThreadId()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if err := c.checkNotBlocked(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	var root *messagev1.Message
+	if threadID != (gocql.UUID{}) {
+		if root, err = c.threadRoot(ctx, convID, threadID); err != nil {
+			c.observeError(op, "cassandra")
+			return nil, err
+		}
+		// a deleted message can keep its thread going, but not start one
+		if root.Deleted && root.ReplyCount == 0 {
+			return nil, status.Error(codes.FailedPrecondition, "message has been deleted")
+		}
+	}
+
+	id := gocql.TimeUUID()
+	msg := &messagev1.Message{
+		Id:             id.String(),
+		ConversationId: convID.String(),
+		SenderId:       caller.String(),
+		Body:           body,
+		CreatedAt:      timestamppb.New(id.Time()),
+		ThreadId:       root.GetId(),
+	}
+	if err := c.h.InsertMessage(ctx, msg); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	// the message is stored either way; failing here would only make the
+	// client resend it
+	if err := c.conversations.Touch(ctx, convID, id.Time()); err != nil {
+		slog.Warn("failed to bump conversation activity",
+			slog.String("conversation_id", convID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if root != nil {
+		c.recordReply(ctx, convID, root, id, caller)
+	}
+
+	members := c.publish(ctx, convID, &realtimev1.Event{
+		ConversationId: msg.ConversationId,
+		OccurredAt:     msg.CreatedAt,
+		Payload:        &realtimev1.Event_MessageCreated{MessageCreated: msg},
+	})
+	// replies show up as unread in their followers' threads instead
+	if root == nil {
+		c.countUnread(ctx, msg, members)
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.SendMessageResponse{Message: msg}, nil
+}
+
+// --- LIST MESSAGES ---
+func (c *MessageController) ListMessages(ctx context.Context, req *messagev1.ListMessagesRequest) (*messagev1.ListMessagesResponse, error) {
+	start := time.Now()
+	const op = "list_messages"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cursor gocql.UUID
+		dir    = handler.Older
+	)
+	switch cur := req.GetCursor().(type) {
+	case *messagev1.ListMessagesRequest_Before:
+		cursor, err = parseCursor("before", cur.Before)
+	case *messagev1.ListMessagesRequest_After:
+		cursor, err = parseCursor("after", cur.After)
+		dir = handler.Newer
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	msgs, more, err := c.h.ListMessages(ctx, convID, cursor, dir, c.pageSize(req.GetPageLimit()))
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if err := c.attachReactions(ctx, msgs, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.ListMessagesResponse{Messages: msgs, HasMore: more}, nil
+}
+
+// publish appends ev to the event logs of conversation convID's members,
+// the sender's included so their other devices see it, and returns the
+// member ids, or nil when they could not be looked up. Failures are only
+// logged: the message is stored, and clients that miss the event catch up
+// by listing.
+func (c *MessageController) publish(ctx context.Context, convID gocql.UUID, ev *realtimev1.Event) []string {
+	members, err := c.conversations.Members(ctx, convID)
+	var ids []string
+	if err == nil {
+		ids = make([]string, len(members))
+		for i, m := range members {
+			ids[i] = m.UserId
+		}
+		err = c.events.Append(ctx, ev, ids...)
+	}
+	if err != nil {
+		slog.Warn("failed to publish message event",
+			slog.String("conversation_id", convID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+	return ids
+}
+
+// checkMember verifies caller belongs to conversation convID. A conversation
+// the caller is not in is reported as not found, so its existence is not
+// revealed.
+func (c *MessageController) checkMember(ctx context.Context, convID, caller gocql.UUID) error {
+	_, err := c.conversations.Member(ctx, convID, caller)
+	if status.Code(err) == codes.NotFound {
+		return status.Error(codes.NotFound, "conversation not found")
+	}
+	return err
+}
+
+// checkNotBlocked refuses caller's message to a direct conversation when
+// either side has blocked the other. Group conversations are not checked;
+// blocks there only keep people from being added.
+func (c *MessageController) checkNotBlocked(ctx context.Context, convID, caller gocql.UUID) error {
+	conv, err := c.conversations.GetConversation(ctx, convID)
+	if err != nil {
+		return err
+	}
+	if conv.GetType() != conversationv1.ConversationType_CONVERSATION_TYPE_DIRECT {
+		return nil
+	}
+
+	members, err := c.conversations.Members(ctx, convID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		other, err := gocql.ParseUUID(m.GetUserId())
+		if err != nil || other == caller {
+			continue
+		}
+		blocked, err := blocklist.IsBlocked(ctx, c.h.Db, caller, other)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to check blocks: %v", err)
+		}
+		if blocked {
+			return status.Error(codes.PermissionDenied, "cannot message this user")
+		}
+	}
+	return nil
+}
+
+// parseCursor validates a message id used as a list cursor. Message ids are
+// time-based, and the time in them picks the bucket to start from.
+func parseCursor(field, value string) (gocql.UUID, error) {
+	id, err := parseUUID(field, value)
+	if err != nil {
+		return gocql.UUID{}, err
+	}
+	if id.Version() != 1 {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "invalid %s: not a message id", field)
+	}
+	return id, nil
+}
+
+func callerUUID(ctx context.Context) (gocql.UUID, error) {
+	claims, ok := authjwt.ClaimsFromContext(ctx)
+	if !ok || claims.UserID == "" {
+		return gocql.UUID{}, status.Error(codes.Unauthenticated, "missing caller identity")
+	}
+	id, err := gocql.ParseUUID(claims.UserID)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.Unauthenticated, "invalid caller id: %v", err)
+	}
+	return id, nil
+}
+
+// parseUUID validates a UUID request field, naming the field on failure.
+func parseUUID(field, value string) (gocql.UUID, error) {
+	if value == "" {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	id, err := gocql.ParseUUID(value)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+	}
+	return id, nil
+}
+
+// pageSize returns the number of messages to list for a request's page
+// limit: the configured default when unset, capped at the configured maximum.
+func (c *MessageController) pageSize(pageLimit uint32) int {
+	maxSize := defaultMaxPageSize
+	if n := c.Config.Message.MaxPageSize; n > 0 {
+		maxSize = n
+	}
+	if pageLimit == 0 {
+		size := defaultMessagePageSize
+		if n := c.Config.Message.DefaultPageSize; n > 0 {
+			size = n
+		}
+		return min(size, maxSize)
+	}
+	return int(min(pageLimit, uint32(maxSize)))
+}
+
+// validateBody checks a message body is not blank and fits the configured
+// length.
+func (c *MessageController) validateBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return status.Error(codes.InvalidArgument, "body is required")
+	}
+	if n, maxLen := utf8.RuneCountInString(body), c.maxBodyLength(); n > maxLen {
+		return status.Errorf(codes.InvalidArgument, "body must be at most %d characters, got %d", maxLen, n)
+	}
+	return nil
+}
+
+func (c *MessageController) maxBodyLength() int {
+	if n := c.Config.Message.MaxBodyLength; n > 0 {
+		return n
+	}
+	return defaultMaxBodyLength
+}
+
+func (c *MessageController) observeDuration(op, db string, start time.Time) {
+	c.M.Duration.WithLabelValues(op, db).Observe(time.Since(start).Seconds())
+}
+
+func (c *MessageController) observeError(op, db string) {
+	c.M.Errors.WithLabelValues(op, db).Inc()
+}
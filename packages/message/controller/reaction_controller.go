@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/message/emoji"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultMaxReactionsPerUser bounds the distinct emoji one member may react
+// to a single message with.
+const defaultMaxReactionsPerUser = 20
+
+// --- ADD REACTION ---
+func (c *MessageController) AddReaction(ctx context.Context, req *messagev1.AddReactionRequest) (*messagev1.AddReactionResponse, error) {
+	start := time.Now()
+	const op = "add_reaction"
+
+	reactions, err := c.react(ctx, op, req.GetConversationId(), req.GetMessageId(), req.GetEmoji(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.AddReactionResponse{Reactions: reactions}, nil
+}
+
+// --- REMOVE REACTION ---
+func (c *MessageController) RemoveReaction(ctx context.Context, req *messagev1.RemoveReactionRequest) (*messagev1.RemoveReactionResponse, error) {
+	start := time.Now()
+	const op = "remove_reaction"
+
+	reactions, err := c.react(ctx, op, req.GetConversationId(), req.GetMessageId(), req.GetEmoji(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.RemoveReactionResponse{Reactions: reactions}, nil
+}
+
+// react adds or removes the caller's reaction to a message and returns the
+// message's reactions afterwards. Members are told only when the reaction
+// actually changed.
+func (c *MessageController) react(ctx context.Context, op, conversationID, messageID, reaction string, add bool) ([]*messagev1.ReactionSummary, error) {
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", conversationID)
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := parseCursor("message_id", messageID)
+	if err != nil {
+		return nil, err
+	}
+	if reaction == "" {
+		return nil, status.Error(codes.InvalidArgument, "emoji is required")
+	}
+	// removing stays possible after a custom emoji is retired
+	if add && !emoji.Valid(reaction, c.Config.Reactions.CustomEmoji) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid emoji: %q", reaction)
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	msg, err := c.h.GetMessage(ctx, convID, msgID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if msg.Deleted {
+		return nil, status.Error(codes.FailedPrecondition, "message has been deleted")
+	}
+
+	var changed bool
+	if add {
+		if err := c.checkReactionLimit(ctx, msgID, caller, reaction); err != nil {
+			c.observeError(op, "cassandra")
+			return nil, err
+		}
+		changed, err = c.h.AddReaction(ctx, msgID, caller, reaction, time.Now())
+	} else {
+		changed, err = c.h.RemoveReaction(ctx, msgID, caller, reaction)
+	}
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	summaries, err := c.h.Reactions(ctx, []gocql.UUID{msgID}, caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	reactions := summaries[msg.Id]
+
+	if changed {
+		ev := &messagev1.ReactionEvent{
+			MessageId: msg.Id,
+			UserId:    caller.String(),
+			Emoji:     reaction,
+			Added:     add,
+		}
+		for _, r := range reactions {
+			if r.Emoji == reaction {
+				ev.Count = r.Count
+			}
+		}
+		c.publish(ctx, convID, &realtimev1.Event{
+			ConversationId: msg.ConversationId,
+			OccurredAt:     timestamppb.Now(),
+			Payload:        &realtimev1.Event_ReactionUpdated{ReactionUpdated: ev},
+		})
+	}
+	return reactions, nil
+}
+
+// checkReactionLimit fails with ResourceExhausted when caller already reacted
+// to message msgID with as many emoji as allowed, none of them reaction.
+// Reactions added concurrently can go past the limit by as many.
+func (c *MessageController) checkReactionLimit(ctx context.Context, msgID, caller gocql.UUID, reaction string) error {
+	mine, err := c.h.UserReactions(ctx, msgID, caller)
+	if err != nil {
+		return err
+	}
+	limit := c.maxReactionsPerUser()
+	if len(mine) >= limit && !slices.Contains(mine, reaction) {
+		return status.Errorf(codes.ResourceExhausted, "at most %d reactions per message", limit)
+	}
+	return nil
+}
+
+func (c *MessageController) maxReactionsPerUser() int {
+	if n := c.Config.Reactions.MaxPerUser; n > 0 {
+		return n
+	}
+	return defaultMaxReactionsPerUser
+}
+
+// attachReactions fills in the reactions of msgs as caller sees them.
+// Tombstones keep none.
+func (c *MessageController) attachReactions(ctx context.Context, msgs []*messagev1.Message, caller gocql.UUID) error {
+	ids := make([]gocql.UUID, 0, len(msgs))
+	for _, m := range msgs {
+		if m.Deleted {
+			continue
+		}
+		if id, err := gocql.ParseUUID(m.Id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	summaries, err := c.h.Reactions(ctx, ids, caller)
+	if err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		if !m.Deleted {
+			m.Reactions = summaries[m.Id]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- MARK READ ---
+func (c *MessageController) MarkRead(ctx context.Context, req *messagev1.MarkReadRequest) (*messagev1.MarkReadResponse, error) {
+	start := time.Now()
+	const op = "mark_read"
+
+	// reading a message means it was delivered too
+	receipt, err := c.markReceipt(ctx, op, req.GetConversationId(), req.GetMessageId(), handler.ReadCursor, handler.DeliveredCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.MarkReadResponse{Receipt: receipt}, nil
+}
+
+// --- MARK DELIVERED ---
+func (c *MessageController) MarkDelivered(ctx context.Context, req *messagev1.MarkDeliveredRequest) (*messagev1.MarkDeliveredResponse, error) {
+	start := time.Now()
+	const op = "mark_delivered"
+
+	receipt, err := c.markReceipt(ctx, op, req.GetConversationId(), req.GetMessageId(), handler.DeliveredCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.MarkDeliveredResponse{Receipt: receipt}, nil
+}
+
+// --- GET RECEIPTS ---
+func (c *MessageController) GetReceipts(ctx context.Context, req *messagev1.GetReceiptsRequest) (*messagev1.GetReceiptsResponse, error) {
+	start := time.Now()
+	const op = "get_receipts"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	receipts, err := c.h.ListReceipts(ctx, convID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.GetReceiptsResponse{Receipts: receipts}, nil
+}
+
+// markReceipt moves the caller's cursors in a conversation forward to a
+// message and returns the resulting receipt. Members are told only when a
+// cursor actually moved, and a moved read cursor resets the unread counts.
+func (c *MessageController) markReceipt(ctx context.Context, op, conversationID, messageID string, cursors ...handler.Cursor) (*messagev1.Receipt, error) {
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", conversationID)
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := parseCursor("message_id", messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	exists, err := c.h.MessageExists(ctx, convID, msgID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, "message not found")
+	}
+
+	now := time.Now()
+	moved, readMoved := false, false
+	for _, cursor := range cursors {
+		ok, err := c.h.AdvanceCursor(ctx, convID, caller, cursor, msgID, now)
+		if err != nil {
+			c.observeError(op, "cassandra")
+			return nil, err
+		}
+		moved = moved || ok
+		readMoved = readMoved || (ok && cursor == handler.ReadCursor)
+	}
+
+	receipt, err := c.h.GetReceipt(ctx, convID, caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	if moved {
+		c.publish(ctx, convID, &realtimev1.Event{
+			ConversationId: convID.String(),
+			OccurredAt:     timestamppb.New(now),
+			Payload:        &realtimev1.Event_ReceiptUpdated{ReceiptUpdated: receipt},
+		})
+	}
+	if readMoved {
+		if err := c.reconcile(ctx, caller, convID); err != nil {
+			slog.Warn("failed to reset unread counts",
+				slog.String("conversation_id", convID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return receipt, nil
+}
@@ -0,0 +1,411 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+	"github.com/yaninyzwitty/chat/packages/shared/pubsub"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- LIST THREAD REPLIES ---
+func (c *MessageController) ListThreadReplies(ctx context.Context, req *messagev1.ListThreadRepliesRequest) (*messagev1.ListThreadRepliesResponse, error) {
+	start := time.Now()
+	const op = "list_thread_replies"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+	threadID, err := parseCursor("thread_id", req.GetThreadId())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cursor gocql.UUID
+		dir    = handler.Older
+	)
+	switch cur := req.GetCursor().(type) {
+	case *messagev1.ListThreadRepliesRequest_Before:
+		cursor, err = parseCursor("before", cur.Before)
+	case *messagev1.ListThreadRepliesRequest_After:
+		cursor, err = parseCursor("after", cur.After)
+		dir = handler.Newer
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	root, err := c.threadRoot(ctx, convID, threadID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	replies, more, err := c.h.ListThreadReplies(ctx, convID, threadID, cursor, dir, c.pageSize(req.GetPageLimit()))
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if err := c.attachReactions(ctx, append([]*messagev1.Message{root}, replies...), caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	// the page holds the newest reply when it starts from the newest end, or
+	// pages forward up to it
+	var newest *messagev1.Message
+	switch {
+	case len(replies) == 0:
+	case dir == handler.Older && cursor == (gocql.UUID{}):
+		newest = replies[0]
+	case dir == handler.Newer && !more:
+		newest = replies[len(replies)-1]
+	}
+	if newest != nil {
+		c.markThreadRead(ctx, caller, threadID, newest)
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.ListThreadRepliesResponse{Root: root, Replies: replies, HasMore: more}, nil
+}
+
+// --- FOLLOW THREAD ---
+func (c *MessageController) FollowThread(ctx context.Context, req *messagev1.FollowThreadRequest) (*messagev1.FollowThreadResponse, error) {
+	start := time.Now()
+	const op = "follow_thread"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+	threadID, err := parseCursor("thread_id", req.GetThreadId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMember(ctx, convID, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if _, err := c.threadRoot(ctx, convID, threadID); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	// replies from before following are not new to the follower
+	if err := c.h.FollowThread(ctx, caller, convID, threadID, true, time.Now()); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.FollowThreadResponse{}, nil
+}
+
+// --- UNFOLLOW THREAD ---
+func (c *MessageController) UnfollowThread(ctx context.Context, req *messagev1.UnfollowThreadRequest) (*messagev1.UnfollowThreadResponse, error) {
+	start := time.Now()
+	const op = "unfollow_thread"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseUUID("conversation_id", req.GetConversationId()); err != nil {
+		return nil, err
+	}
+	threadID, err := parseCursor("thread_id", req.GetThreadId())
+	if err != nil {
+		return nil, err
+	}
+
+	// no membership check, so threads in conversations the caller has left
+	// can still be dropped
+	if err := c.h.UnfollowThread(ctx, caller, threadID); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &messagev1.UnfollowThreadResponse{}, nil
+}
+
+// --- LIST MY THREADS ---
+func (c *MessageController) ListMyThreads(ctx context.Context, req *messagev1.ListMyThreadsRequest) (*messagev1.ListMyThreadsResponse, error) {
+	start := time.Now()
+	const op = "list_my_threads"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var before handler.ThreadCursor
+	if cur := req.GetBefore(); cur != nil {
+		if err := cur.GetLastActiveAt().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid before.last_active_at: %v", err)
+		}
+		threadID, err := parseCursor("before.thread_id", cur.GetThreadId())
+		if err != nil {
+			return nil, err
+		}
+		before = handler.ThreadCursor{LastActive: cur.GetLastActiveAt().AsTime(), ThreadID: threadID}
+	}
+
+	// threads left out still use up the page, so one call reads at most a
+	// page of them
+	followed, next, more, err := c.h.ListFollowedThreads(ctx, caller, before, c.pageSize(req.GetPageLimit()))
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if req.GetUnreadOnly() {
+		followed = slices.DeleteFunc(followed, func(t *handler.FollowedThread) bool { return !t.Unread() })
+	}
+	threads, err := c.threadSummaries(ctx, caller, followed)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	roots := make([]*messagev1.Message, len(threads))
+	for i, t := range threads {
+		roots[i] = t.Root
+	}
+	if err := c.attachReactions(ctx, roots, caller); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	resp := &messagev1.ListMyThreadsResponse{Threads: threads, HasMore: more}
+	if more {
+		resp.Next = &messagev1.ThreadCursor{
+			LastActiveAt: timestamppb.New(next.LastActive),
+			ThreadId:     next.ThreadID.String(),
+		}
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return resp, nil
+}
+
+// threadSummaries describes followed threads to caller, in the same order,
+// leaving out threads in conversations caller has since left and threads
+// whose root is gone. Membership is checked and roots are read once per
+// conversation.
+func (c *MessageController) threadSummaries(ctx context.Context, caller gocql.UUID, threads []*handler.FollowedThread) ([]*messagev1.ThreadSummary, error) {
+	member := map[gocql.UUID]bool{}
+	byConv := map[gocql.UUID][]gocql.UUID{}
+	for _, t := range threads {
+		if _, checked := member[t.ConversationID]; !checked {
+			_, err := c.conversations.Member(ctx, t.ConversationID, caller)
+			if err != nil && status.Code(err) != codes.NotFound {
+				return nil, err
+			}
+			member[t.ConversationID] = err == nil
+		}
+		if member[t.ConversationID] {
+			byConv[t.ConversationID] = append(byConv[t.ConversationID], t.ThreadID)
+		}
+	}
+
+	roots := map[gocql.UUID]*messagev1.Message{}
+	for convID, ids := range byConv {
+		found, err := c.h.GetMessages(ctx, convID, ids)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(roots, found)
+	}
+
+	summaries := make([]*messagev1.ThreadSummary, 0, len(threads))
+	for _, t := range threads {
+		root, ok := roots[t.ThreadID]
+		if !ok {
+			continue
+		}
+		summary := &messagev1.ThreadSummary{
+			Root:         root,
+			LastActiveAt: timestamppb.New(t.LastActive()),
+		}
+		if t.Unread() {
+			n, err := c.h.CountReplies(ctx, t.ThreadID, t.ReadReplyID, c.maxUnread())
+			if err != nil {
+				return nil, err
+			}
+			summary.UnreadReplies = uint32(n)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// threadRoot returns message threadID of conversation convID as the root of
+// a thread. Replies cannot root threads of their own.
+func (c *MessageController) threadRoot(ctx context.Context, convID, threadID gocql.UUID) (*messagev1.Message, error) {
+	root, err := c.h.GetMessage(ctx, convID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if root.ThreadId != "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid thread_id: replies cannot have threads")
+	}
+	return root, nil
+}
+
+// Replies are fanned out to a thread's followers by a worker, off the send
+// path: each reply is queued on this topic and taken by one consumer of the
+// group, across all instances.
+const (
+	threadReplyTopic = "thread-replies"
+	threadReplyGroup = "thread-followers"
+
+	// fanoutRetry is how long the worker waits to subscribe again after the
+	// broker fails
+	fanoutRetry = 5 * time.Second
+)
+
+// recordReply counts reply replyID by replier in the thread of root, and
+// brings the thread's followers up to date: the replier follows it, caught
+// up to their reply, and the root's author follows it from the first reply.
+// The other followers are queued for RunThreadFanout. Failures are only
+// logged; the reply is stored and listed in its thread either way.
+func (c *MessageController) recordReply(ctx context.Context, convID gocql.UUID, root *messagev1.Message, replyID, replier gocql.UUID) {
+	threadID, err := gocql.ParseUUID(root.Id)
+	if err != nil {
+		return
+	}
+	warn := func(msg string, err error) {
+		slog.Warn(msg,
+			slog.String("conversation_id", convID.String()),
+			slog.String("thread_id", root.Id),
+			slog.String("error", err.Error()),
+		)
+	}
+	now := time.Now()
+
+	// the author follows from the first reply; when the reply could not be
+	// counted it may have been the first, and following again changes nothing
+	count, countErr := c.h.CountReply(ctx, convID, threadID, replyID)
+	if countErr != nil {
+		warn("failed to count thread reply", countErr)
+	}
+	if author, err := gocql.ParseUUID(root.SenderId); err == nil && (count == 1 || countErr != nil) && author != replier {
+		if err := c.h.FollowThread(ctx, author, convID, threadID, false, now); err != nil {
+			warn("failed to follow thread for its author", err)
+		}
+	}
+	if err := c.h.FollowThread(ctx, replier, convID, threadID, true, now); err != nil {
+		warn("failed to follow thread", err)
+	}
+	if _, err := c.h.MarkThreadRead(ctx, replier, threadID, replyID); err != nil {
+		warn("failed to mark thread read", err)
+	}
+
+	job := append(threadID.Bytes(), replyID.Bytes()...)
+	if err := c.broker.Publish(ctx, job, threadReplyTopic); err != nil {
+		warn("failed to queue thread followers", err)
+	}
+}
+
+// RunThreadFanout updates the followers of threads that got replies, as
+// queued by recordReply, until ctx is cancelled. A reply whose followers
+// could not all be updated stays queued and is retried.
+func (c *MessageController) RunThreadFanout(ctx context.Context) error {
+	// replies left with a consumer that is gone are taken over by the others
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = gocql.TimeUUID().String()
+	}
+	for {
+		err := c.broker.Subscribe(ctx, threadReplyTopic, threadReplyGroup, consumer, c.fanOutReply)
+		if ctx.Err() != nil {
+			return nil
+		}
+		slog.Warn("thread fan-out stopped, retrying", "error", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(fanoutRetry):
+		}
+	}
+}
+
+// fanOutReply touches the followed threads of every follower of the reply in
+// msg.
+func (c *MessageController) fanOutReply(ctx context.Context, msg *pubsub.Message) error {
+	if len(msg.Data) != 32 {
+		// retrying cannot fix it
+		slog.Warn("dropping malformed thread reply", "id", msg.ID)
+		return nil
+	}
+	threadID, _ := gocql.UUIDFromBytes(msg.Data[:16])
+	replyID, _ := gocql.UUIDFromBytes(msg.Data[16:])
+
+	followers, err := c.h.ThreadFollowers(ctx, threadID)
+	if err != nil {
+		return err
+	}
+	return c.h.TouchFollowedThreads(ctx, threadID, followers, replyID)
+}
+
+// uncountReply takes deleted reply out of its thread's reply count. Failures
+// are only logged; the reply is deleted either way.
+func (c *MessageController) uncountReply(ctx context.Context, convID gocql.UUID, reply *messagev1.Message) {
+	threadID, err := gocql.ParseUUID(reply.ThreadId)
+	if err != nil {
+		return
+	}
+	replyID, err := gocql.ParseUUID(reply.Id)
+	if err != nil {
+		return
+	}
+	if err := c.h.UncountReply(ctx, convID, threadID, replyID); err != nil {
+		slog.Warn("failed to uncount thread reply",
+			slog.String("conversation_id", convID.String()),
+			slog.String("thread_id", reply.ThreadId),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// markThreadRead moves caller's read cursor in thread threadID up to reply,
+// if caller follows the thread. Failures are only logged; the replies were
+// listed all the same.
+func (c *MessageController) markThreadRead(ctx context.Context, caller, threadID gocql.UUID, reply *messagev1.Message) {
+	replyID, err := gocql.ParseUUID(reply.Id)
+	if err != nil {
+		return
+	}
+	followed, err := c.h.GetFollowedThread(ctx, caller, threadID)
+	if err == nil && followed != nil {
+		_, err = c.h.MarkThreadRead(ctx, caller, threadID, replyID)
+	}
+	if err != nil {
+		slog.Warn("failed to mark thread read",
+			slog.String("thread_id", threadID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+	"github.com/yaninyzwitty/chat/packages/message/unread"
+	"github.com/yaninyzwitty/chat/packages/shared/blocklist"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxUnread         = 100
+	defaultReconcileInterval = 10 * time.Second
+	defaultReconcileBatch    = 200
+)
+
+// --- GET UNREAD COUNTS ---
+func (c *MessageController) GetUnreadCounts(ctx context.Context, req *messagev1.GetUnreadCountsRequest) (*messagev1.GetUnreadCountsResponse, error) {
+	start := time.Now()
+	const op = "get_unread_counts"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := c.unread.Get(ctx, caller.String())
+	if err != nil {
+		c.observeError(op, "redis")
+		return nil, status.Errorf(codes.Internal, "failed to get unread counts: %v", err)
+	}
+
+	maxCount := int64(c.maxUnread())
+	resp := &messagev1.GetUnreadCountsResponse{}
+	for convID, n := range counts {
+		count := &messagev1.UnreadCount{
+			ConversationId: convID,
+			Unread:         uint32(min(n.Unread, maxCount)),
+			Mentions:       uint32(min(n.Mentions, n.Unread, maxCount)),
+		}
+		resp.TotalUnread += count.Unread
+		resp.TotalMentions += count.Mentions
+		resp.Conversations = append(resp.Conversations, count)
+	}
+	slices.SortFunc(resp.Conversations, func(a, b *messagev1.UnreadCount) int {
+		return cmp.Compare(a.ConversationId, b.ConversationId)
+	})
+
+	c.observeDuration(op, "redis", start)
+	return resp, nil
+}
+
+// RunUnreadReconciler recomputes the unread counts sends have touched from
+// Cassandra, a batch per interval, until ctx is cancelled.
+func (c *MessageController) RunUnreadReconciler(ctx context.Context) error {
+	interval := defaultReconcileInterval
+	if n := c.Config.Unread.ReconcileInterval; n > 0 {
+		interval = time.Duration(n) * time.Second
+	}
+	batch := defaultReconcileBatch
+	if n := c.Config.Unread.ReconcileBatch; n > 0 {
+		batch = n
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			pairs, err := c.unread.ClaimDirty(ctx, int64(batch))
+			if err != nil {
+				slog.Warn("failed to claim unread counts", "error", err)
+				continue
+			}
+			var failed []unread.Pair
+			for _, p := range pairs {
+				userID, err1 := gocql.ParseUUID(p.UserID)
+				convID, err2 := gocql.ParseUUID(p.ConversationID)
+				if err1 != nil || err2 != nil {
+					continue
+				}
+				if err := c.reconcile(ctx, userID, convID); err != nil {
+					slog.Warn("failed to reconcile unread counts",
+						slog.String("user_id", p.UserID),
+						slog.String("conversation_id", p.ConversationID),
+						slog.String("error", err.Error()),
+					)
+					failed = append(failed, p)
+				}
+			}
+			// put them back for the next round
+			if err := c.unread.MarkDirty(ctx, failed...); err != nil {
+				slog.Warn("failed to requeue unread counts", "error", err)
+			}
+		}
+	}
+}
+
+// reconcile recomputes userID's counts in conversation convID from the
+// messages after their read cursor, stopping at the configured maximum.
+// Messages from users they muted are not counted.
+func (c *MessageController) reconcile(ctx context.Context, userID, convID gocql.UUID) error {
+	var counts unread.Counts
+	_, err := c.conversations.Member(ctx, convID, userID)
+	if status.Code(err) == codes.NotFound {
+		// left since; nothing there is theirs to read
+		return c.unread.Set(ctx, userID.String(), convID.String(), counts)
+	}
+	if err != nil {
+		return err
+	}
+
+	receipt, err := c.h.GetReceipt(ctx, convID, userID)
+	if err != nil {
+		return err
+	}
+	var cursor gocql.UUID
+	if id := receipt.GetReadMessageId(); id != "" {
+		if cursor, err = gocql.ParseUUID(id); err != nil {
+			return err
+		}
+	}
+	muted, err := blocklist.Muted(ctx, c.h.Db, userID)
+	if err != nil {
+		return err
+	}
+
+	maxCount := c.maxUnread()
+	self := userID.String()
+	for counts.Unread < int64(maxCount) {
+		msgs, more, err := c.h.ListMessages(ctx, convID, cursor, handler.Newer, maxCount)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.SenderId == self || m.Deleted {
+				continue
+			}
+			if sender, err := gocql.ParseUUID(m.SenderId); err == nil && muted[sender] {
+				continue
+			}
+			counts.Unread++
+			if slices.Contains(unread.Mentions(m.Body), self) {
+				counts.Mentions++
+			}
+		}
+		if !more || len(msgs) == 0 {
+			break
+		}
+		cursor, _ = gocql.ParseUUID(msgs[len(msgs)-1].Id)
+	}
+	counts.Unread = min(counts.Unread, int64(maxCount))
+	counts.Mentions = min(counts.Mentions, counts.Unread)
+	return c.unread.Set(ctx, self, convID.String(), counts)
+}
+
+// countUnread counts msg as unread for the members other than its sender,
+// leaving out those who muted the sender. Failures are only logged; the
+// reconciler fixes the counts.
+func (c *MessageController) countUnread(ctx context.Context, msg *messagev1.Message, members []string) {
+	muted := c.mutedBy(ctx, msg.SenderId, members)
+	var recipients, mentioned []string
+	mentions := unread.Mentions(msg.Body)
+	for _, id := range members {
+		if id == msg.SenderId || muted[id] {
+			continue
+		}
+		recipients = append(recipients, id)
+		if slices.Contains(mentions, id) {
+			mentioned = append(mentioned, id)
+		}
+	}
+	if err := c.unread.Increment(ctx, msg.ConversationId, recipients, mentioned); err != nil {
+		slog.Warn("failed to count unread message",
+			slog.String("conversation_id", msg.ConversationId),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// recountUnread queues the counts of the members other than msg's sender for
+// reconciliation, which leaves msg out once it is deleted.
+func (c *MessageController) recountUnread(ctx context.Context, msg *messagev1.Message, members []string) {
+	var pairs []unread.Pair
+	for _, id := range members {
+		if id != msg.SenderId {
+			pairs = append(pairs, unread.Pair{UserID: id, ConversationID: msg.ConversationId})
+		}
+	}
+	if err := c.unread.MarkDirty(ctx, pairs...); err != nil {
+		slog.Warn("failed to queue unread counts",
+			slog.String("conversation_id", msg.ConversationId),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// mutedBy returns which of members muted sender. When that cannot be told,
+// none did; the reconciler drops what was counted wrongly.
+func (c *MessageController) mutedBy(ctx context.Context, sender string, members []string) map[string]bool {
+	senderID, err := gocql.ParseUUID(sender)
+	if err != nil {
+		return nil
+	}
+	ids := make([]gocql.UUID, 0, len(members))
+	for _, id := range members {
+		if u, err := gocql.ParseUUID(id); err == nil && u != senderID {
+			ids = append(ids, u)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	muters, err := blocklist.MutedBy(ctx, c.h.Db, senderID, ids)
+	if err != nil {
+		slog.Warn("failed to check mutes",
+			slog.String("sender_id", sender),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	out := make(map[string]bool, len(muters))
+	for id := range muters {
+		out[id.String()] = true
+	}
+	return out
+}
+
+func (c *MessageController) maxUnread() int {
+	if n := c.Config.Unread.MaxCount; n > 0 {
+		return n
+	}
+	return defaultMaxUnread
+}
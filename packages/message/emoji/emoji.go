@@ -0,0 +1,137 @@
+// Package emoji decides what may be used as a reaction: a single Unicode
+// emoji, including skin tone, keycap, flag and ZWJ sequences, or one of the
+// deployment's custom emoji written as :name:.
+package emoji
+
+import (
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// maxLength bounds a reaction in bytes. The longest emoji ZWJ sequences in
+// use, such as families with skin tones, stay well under it.
+const maxLength = 64
+
+// customPattern is the form of a custom emoji name.
+var customPattern = regexp.MustCompile(`^:[a-z0-9_+-]{1,32}:$`)
+
+const (
+	zwj           = 0x200D
+	keycap        = 0x20E3
+	textStyle     = 0xFE0E
+	emojiStyle    = 0xFE0F
+	tagCancel     = 0xE007F
+	regionalFirst = 0x1F1E6
+	regionalLast  = 0x1F1FF
+	skinToneFirst = 0x1F3FB
+	skinToneLast  = 0x1F3FF
+	tagFirst      = 0xE0020
+	tagLast       = 0xE007E
+)
+
+// maxZWJElements is the most pictographs any recommended ZWJ sequence joins,
+// as in families of four or a kiss between two people.
+const maxZWJElements = 4
+
+// pictographic are the ranges of Unicode's Extended_Pictographic property,
+// which every emoji other than keycaps and flags starts with, coalesced.
+var pictographic = [][2]rune{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x2199}, {0x21A9, 0x21AA},
+	{0x231A, 0x231B}, {0x2328, 0x2328}, {0x2388, 0x2388}, {0x23CF, 0x23CF},
+	{0x23E9, 0x23F3}, {0x23F8, 0x23FA}, {0x24C2, 0x24C2}, {0x25AA, 0x25AB},
+	{0x25B6, 0x25B6}, {0x25C0, 0x25C0}, {0x25FB, 0x25FE}, {0x2600, 0x2605},
+	{0x2607, 0x2612}, {0x2614, 0x2685}, {0x2690, 0x2705}, {0x2708, 0x2712},
+	{0x2714, 0x2714}, {0x2716, 0x2716}, {0x271D, 0x271D}, {0x2721, 0x2721},
+	{0x2728, 0x2728}, {0x2733, 0x2734}, {0x2744, 0x2744}, {0x2747, 0x2747},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757},
+	{0x2763, 0x2767}, {0x2795, 0x2797}, {0x27A1, 0x27A1}, {0x27B0, 0x27B0},
+	{0x27BF, 0x27BF}, {0x2934, 0x2935}, {0x2B05, 0x2B07}, {0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x3030, 0x3030}, {0x303D, 0x303D},
+	{0x3297, 0x3297}, {0x3299, 0x3299}, {0x1F000, 0x1F0FF}, {0x1F10D, 0x1F10F},
+	{0x1F12F, 0x1F12F}, {0x1F16C, 0x1F171}, {0x1F17E, 0x1F17F}, {0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A}, {0x1F1AD, 0x1F1E5}, {0x1F201, 0x1F20F}, {0x1F21A, 0x1F21A},
+	{0x1F22F, 0x1F22F}, {0x1F232, 0x1F23A}, {0x1F23C, 0x1F23F}, {0x1F249, 0x1F3FA},
+	{0x1F400, 0x1F53D}, {0x1F546, 0x1F64F}, {0x1F680, 0x1F6FF}, {0x1F774, 0x1F77F},
+	{0x1F7D5, 0x1F7FF}, {0x1F80C, 0x1F80F}, {0x1F848, 0x1F84F}, {0x1F85A, 0x1F85F},
+	{0x1F888, 0x1F88F}, {0x1F8AE, 0x1F8FF}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945},
+	{0x1F947, 0x1FAFF}, {0x1FC00, 0x1FFFD},
+}
+
+// Valid reports whether s may be used as a reaction: a single emoji, or one
+// of custom.
+func Valid(s string, custom []string) bool {
+	if customPattern.MatchString(s) {
+		return slices.Contains(custom, s[1:len(s)-1])
+	}
+	if s == "" || len(s) > maxLength || !utf8.ValidString(s) {
+		return false
+	}
+	runes := []rune(s)
+
+	// flags are a pair of regional indicators and nothing else
+	if isRegional(runes[0]) {
+		return len(runes) == 2 && isRegional(runes[1])
+	}
+	// keycaps are a digit, # or *, optionally styled, then the keycap mark
+	if isKeycapBase(runes[0]) {
+		rest := runes[1:]
+		if len(rest) > 0 && rest[0] == emojiStyle {
+			rest = rest[1:]
+		}
+		return len(rest) == 1 && rest[0] == keycap
+	}
+
+	// anything else is pictographs, each optionally styled or toned, joined
+	// by ZWJs, with tag sequences allowed only on a lone pictograph
+	elements := 0
+	for i := 0; i < len(runes); {
+		if !isPictographic(runes[i]) {
+			return false
+		}
+		elements++
+		i++
+		if i < len(runes) && (runes[i] == emojiStyle || runes[i] == textStyle || isSkinTone(runes[i])) {
+			i++
+		}
+		if i < len(runes) && isTag(runes[i]) {
+			if elements != 1 {
+				return false
+			}
+			for i < len(runes) && isTag(runes[i]) {
+				i++
+			}
+			return i == len(runes)-1 && runes[i] == tagCancel
+		}
+		if i == len(runes) {
+			break
+		}
+		if runes[i] != zwj || i == len(runes)-1 {
+			return false
+		}
+		i++
+	}
+	return elements <= maxZWJElements
+}
+
+func isPictographic(r rune) bool {
+	_, found := slices.BinarySearchFunc(pictographic, r, func(span [2]rune, r rune) int {
+		switch {
+		case r < span[0]:
+			return 1
+		case r > span[1]:
+			return -1
+		}
+		return 0
+	})
+	return found
+}
+
+func isRegional(r rune) bool { return r >= regionalFirst && r <= regionalLast }
+
+func isSkinTone(r rune) bool { return r >= skinToneFirst && r <= skinToneLast }
+
+func isTag(r rune) bool { return r >= tagFirst && r <= tagLast }
+
+func isKeycapBase(r rune) bool { return r == '#' || r == '*' || (r >= '0' && r <= '9') }
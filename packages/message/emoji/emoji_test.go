@@ -0,0 +1,29 @@
+package emoji_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yaninyzwitty/chat/packages/message/emoji"
+)
+
+func TestValid(t *testing.T) {
+	custom := []string{"partyparrot", "ship_it"}
+
+	for _, s := range []string{
+		"👍", "👍🏽", "❤️", "☺", "🇰🇪", "1️⃣", "#⃣",
+		"👩‍💻", "👨‍👩‍👧‍👦", "🏳️‍🌈", "🧑🏿‍🤝‍🧑🏻",
+		"🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F",
+		":partyparrot:", ":ship_it:",
+	} {
+		require.True(t, emoji.Valid(s, custom), "%q", s)
+	}
+
+	for _, s := range []string{
+		"", "a", "ok", "1", "🏽", "‍", "👍👍", "👍 ", "👩‍", "🇰", "🇰🇪🇰",
+		":unknown:", ":PartyParrot:", "partyparrot", "\xff",
+		"👨‍👩‍👧‍👦‍👦",
+	} {
+		require.False(t, emoji.Valid(s, custom), "%q", s)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/yaninyzwitty/chat/packages/message
+
+go 1.25.0
+
+require (
+	github.com/alicebob/miniredis/v2 v2.35.0 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/redis/go-redis/v9 v9.14.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+)
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- DB GET ---
+// GetMessage returns message id of conversation convID, as a tombstone if it
+// was deleted.
+func (h *MessageHandler) GetMessage(ctx context.Context, convID, id gocql.UUID) (*messagev1.Message, error) {
+	var row messageRow
+	err := h.Db.Query(
+		`SELECT `+messageColumns+` FROM chat.messages WHERE conversation_id = ? AND bucket = ? AND message_id = ?`,
+		convID, Bucket(id.Time()), id,
+	).WithContext(ctx).Scan(row.dest()...)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "message not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get message: %v", err)
+	}
+	return row.toProto(convID), nil
+}
+
+// --- DB GET ---
+// GetMessages returns the messages of conversation convID with the given
+// ids, by id. Ids without a message are left out.
+func (h *MessageHandler) GetMessages(ctx context.Context, convID gocql.UUID, ids []gocql.UUID) (map[gocql.UUID]*messagev1.Message, error) {
+	// messages are stored in the buckets of the days they were sent
+	byBucket := map[string][]gocql.UUID{}
+	for _, id := range ids {
+		bucket := Bucket(id.Time())
+		byBucket[bucket] = append(byBucket[bucket], id)
+	}
+
+	found := make(map[gocql.UUID]*messagev1.Message, len(ids))
+	for bucket, bucketIDs := range byBucket {
+		iter := h.Db.Query(
+			`SELECT `+messageColumns+` FROM chat.messages WHERE conversation_id = ? AND bucket = ? AND message_id IN ?`,
+			convID, bucket, bucketIDs,
+		).WithContext(ctx).Iter()
+		var row messageRow
+		for iter.Scan(row.dest()...) {
+			found[row.id] = row.toProto(convID)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get messages: %v", err)
+		}
+	}
+	return found, nil
+}
+
+// --- DB UPDATE ---
+// EditMessage replaces msg's body with body and records the old one as a
+// revision by editor. The update only applies while the stored body is still
+// msg's and the message is not deleted, so concurrent edits cannot drop a
+// revision; a lost race is reported as Aborted. The revision is written
+// first and taken back if the update does not apply, so a delete that
+// follows the edit always finds it to drop.
+func (h *MessageHandler) EditMessage(ctx context.Context, msg *messagev1.Message, body string, editor gocql.UUID, at time.Time) (*messagev1.Message, error) {
+	id, err := gocql.ParseUUID(msg.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	convID, err := gocql.ParseUUID(msg.ConversationId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	revisionID := gocql.UUIDFromTime(at)
+	if err := h.Db.Query(
+		`INSERT INTO chat.message_revisions (message_id, revision_id, conversation_id, editor_id, body) VALUES (?, ?, ?, ?, ?)`,
+		id, revisionID, convID, editor, msg.Body,
+	).WithContext(ctx).Exec(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record message revision: %v", err)
+	}
+
+	prev := map[string]any{}
+	applied, err := h.Db.Query(
+		`UPDATE chat.messages SET body = ?, edited_at = ?
+		 WHERE conversation_id = ? AND bucket = ? AND message_id = ?
+		 IF body = ? AND deleted_at = null`,
+		body, at, convID, Bucket(id.Time()), id, msg.Body,
+	).WithContext(ctx).MapScanCAS(prev)
+	if err != nil || !applied {
+		h.dropRevision(ctx, id, revisionID)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to edit message: %v", err)
+	}
+	if !applied {
+		if deletedAt, _ := prev["deleted_at"].(time.Time); !deletedAt.IsZero() {
+			return nil, status.Error(codes.FailedPrecondition, "message has been deleted")
+		}
+		return nil, status.Error(codes.Aborted, "message was changed concurrently, try again")
+	}
+
+	edited := &messagev1.Message{
+		Id:             msg.Id,
+		ConversationId: msg.ConversationId,
+		SenderId:       msg.SenderId,
+		Body:           body,
+		CreatedAt:      msg.CreatedAt,
+		EditedAt:       timestamppb.New(at),
+		ThreadId:       msg.ThreadId,
+		ReplyCount:     msg.ReplyCount,
+		LastReplyAt:    msg.LastReplyAt,
+	}
+	return edited, nil
+}
+
+// dropRevision takes back the revision of an edit that did not apply. If this
+// fails too the message lists one revision too many until it is deleted.
+func (h *MessageHandler) dropRevision(ctx context.Context, id, revisionID gocql.UUID) {
+	if err := h.Db.Query(
+		`DELETE FROM chat.message_revisions WHERE message_id = ? AND revision_id = ?`, id, revisionID,
+	).WithContext(ctx).Exec(); err != nil {
+		slog.Warn("failed to drop message revision", slog.String("error", err.Error()))
+	}
+}
+
+// --- DB DELETE ---
+// DeleteMessage clears msg's body, marks it deleted by deleter and drops its
+// revisions, leaving a tombstone in the conversation. A deleted root keeps
+// its thread. The delete is a lightweight transaction like EditMessage, so
+// the two serialize and no edit lands after it; deleted is false when the
+// message was already deleted, and the tombstone then carries the earlier
+// deletion time.
+func (h *MessageHandler) DeleteMessage(ctx context.Context, msg *messagev1.Message, deleter gocql.UUID, at time.Time) (tombstone *messagev1.Message, deleted bool, err error) {
+	id, err := gocql.ParseUUID(msg.Id)
+	if err != nil {
+		return nil, false, status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	convID, err := gocql.ParseUUID(msg.ConversationId)
+	if err != nil {
+		return nil, false, status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	prev := map[string]any{}
+	applied, err := h.Db.Query(
+		`UPDATE chat.messages SET body = null, deleted_at = ?, deleted_by = ?
+		 WHERE conversation_id = ? AND bucket = ? AND message_id = ?
+		 IF deleted_at = null`,
+		at, deleter, convID, Bucket(id.Time()), id,
+	).WithContext(ctx).MapScanCAS(prev)
+	if err != nil {
+		return nil, false, status.Errorf(codes.Internal, "failed to delete message: %v", err)
+	}
+	if !applied {
+		if deletedAt, _ := prev["deleted_at"].(time.Time); !deletedAt.IsZero() {
+			at = deletedAt
+		}
+	} else if err := h.Db.Query(
+		// only now can no edit add a revision behind the cleanup
+		`DELETE FROM chat.message_revisions WHERE message_id = ?`, id,
+	).WithContext(ctx).Exec(); err != nil {
+		return nil, false, status.Errorf(codes.Internal, "failed to delete message revisions: %v", err)
+	}
+
+	tombstone = &messagev1.Message{
+		Id:             msg.Id,
+		ConversationId: msg.ConversationId,
+		SenderId:       msg.SenderId,
+		CreatedAt:      msg.CreatedAt,
+		EditedAt:       msg.EditedAt,
+		Deleted:        true,
+		DeletedAt:      timestamppb.New(at),
+		ThreadId:       msg.ThreadId,
+		ReplyCount:     msg.ReplyCount,
+		LastReplyAt:    msg.LastReplyAt,
+	}
+	return tombstone, applied, nil
+}
+
+// --- DB LIST ---
+// ListRevisions returns message id's revisions, oldest first.
+func (h *MessageHandler) ListRevisions(ctx context.Context, id gocql.UUID) ([]*messagev1.MessageRevision, error) {
+	iter := h.Db.Query(
+		`SELECT revision_id, editor_id, body FROM chat.message_revisions WHERE message_id = ?`, id,
+	).WithContext(ctx).Iter()
+
+	var (
+		revisions []*messagev1.MessageRevision
+		revID     gocql.UUID
+		editorID  gocql.UUID
+		body      string
+	)
+	for iter.Scan(&revID, &editorID, &body) {
+		revisions = append(revisions, &messagev1.MessageRevision{
+			Body:     body,
+			EditorId: editorID.String(),
+			EditedAt: timestamppb.New(revID.Time()),
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list message revisions: %v", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/require"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEditAndDeleteMessage(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	convID, sender, admin := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	id := gocql.TimeUUID()
+	require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+		Id:             id.String(),
+		ConversationId: convID.String(),
+		SenderId:       sender.String(),
+		Body:           "helo",
+	}))
+
+	msg, err := h.GetMessage(ctx, convID, id)
+	require.NoError(t, err)
+	edited, err := h.EditMessage(ctx, msg, "hello", sender, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "hello", edited.GetBody())
+	require.NotNil(t, edited.GetEditedAt())
+
+	// an edit based on the old body lost the race
+	_, err = h.EditMessage(ctx, msg, "hello!", sender, time.Now())
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	revisions, err := h.ListRevisions(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Equal(t, "helo", revisions[0].GetBody())
+	require.Equal(t, sender.String(), revisions[0].GetEditorId())
+
+	_, deleted, err := h.DeleteMessage(ctx, edited, admin, time.Now())
+	require.NoError(t, err)
+	require.True(t, deleted)
+	_, deleted, err = h.DeleteMessage(ctx, edited, admin, time.Now())
+	require.NoError(t, err)
+	require.False(t, deleted)
+
+	msgs, _, err := h.ListMessages(ctx, convID, gocql.UUID{}, handler.Older, 10)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.True(t, msgs[0].GetDeleted())
+	require.Empty(t, msgs[0].GetBody())
+
+	revisions, err = h.ListRevisions(ctx, id)
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+
+	_, err = h.EditMessage(ctx, edited, "back", sender, time.Now())
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
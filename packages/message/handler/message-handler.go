@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// bucketLayout names the UTC day a message falls in. It sorts in time order,
+// which listing relies on to walk buckets.
+const bucketLayout = "2006-01-02"
+
+// Direction is the way ListMessages pages from its cursor.
+type Direction int
+
+const (
+	// Older lists messages sent before the cursor, newest first.
+	Older Direction = iota
+	// Newer lists messages sent after the cursor, oldest first.
+	Newer
+)
+
+type MessageHandler struct {
+	Db *gocql.Session
+}
+
+func NewMessageHandler(db *gocql.Session) *MessageHandler {
+	return &MessageHandler{Db: db}
+}
+
+// Bucket returns the partition bucket of a message sent at t.
+func Bucket(t time.Time) string {
+	return t.UTC().Format(bucketLayout)
+}
+
+// --- DB INSERT ---
+// InsertMessage stores msg, whose id must be a time-based UUID; its bucket is
+// taken from the time in the id. The bucket is recorded first, so a stored
+// message is always reachable by ListMessages. A reply, with ThreadId set, is
+// stored alongside the conversation's messages and then listed in its thread.
+func (h *MessageHandler) InsertMessage(ctx context.Context, msg *messagev1.Message) error {
+	id, err := gocql.ParseUUID(msg.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	convID, err := gocql.ParseUUID(msg.ConversationId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	senderID, err := gocql.ParseUUID(msg.SenderId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+	bucket := Bucket(id.Time())
+
+	insert := `INSERT INTO chat.messages (conversation_id, bucket, message_id, sender_id, body) VALUES (?, ?, ?, ?, ?)`
+	values := []any{convID, bucket, id, senderID, msg.Body}
+	var threadID gocql.UUID
+	if msg.ThreadId != "" {
+		if threadID, err = gocql.ParseUUID(msg.ThreadId); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+		}
+		insert = `INSERT INTO chat.messages (conversation_id, bucket, message_id, sender_id, body, thread_id) VALUES (?, ?, ?, ?, ?, ?)`
+		values = append(values, threadID)
+	}
+
+	if err := h.Db.Query(
+		`INSERT INTO chat.message_buckets (conversation_id, bucket) VALUES (?, ?)`,
+		convID, bucket,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to insert message bucket: %v", err)
+	}
+
+	if err := h.Db.Query(insert, values...).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to insert message: %v", err)
+	}
+
+	if msg.ThreadId != "" {
+		if err := h.Db.Query(
+			`INSERT INTO chat.thread_replies (thread_id, message_id) VALUES (?, ?)`,
+			threadID, id,
+		).WithContext(ctx).Exec(); err != nil {
+			return status.Errorf(codes.Internal, "failed to insert thread reply: %v", err)
+		}
+	}
+	return nil
+}
+
+// --- DB LIST ---
+// ListMessages returns up to limit messages of conversation convID on the dir
+// side of cursor, walking the conversation's buckets until the page is full.
+// A zero cursor starts from the newest message for Older and the oldest for
+// Newer. more reports whether further messages lie beyond the page. Thread
+// replies are left out; ListThreadReplies lists them.
+func (h *MessageHandler) ListMessages(ctx context.Context, convID, cursor gocql.UUID, dir Direction, limit int) ([]*messagev1.Message, bool, error) {
+	bucketQuery := `SELECT bucket FROM chat.message_buckets WHERE conversation_id = ?`
+	messageQuery := `SELECT ` + messageColumns + ` FROM chat.messages WHERE conversation_id = ? AND bucket = ?`
+	bucketArgs := []any{convID}
+	var cursorArgs []any
+
+	hasCursor := cursor != (gocql.UUID{})
+	switch dir {
+	case Older:
+		if hasCursor {
+			bucketQuery += ` AND bucket <= ?`
+			messageQuery += ` AND message_id < ?`
+			bucketArgs = append(bucketArgs, Bucket(cursor.Time()))
+			cursorArgs = append(cursorArgs, cursor)
+		}
+	case Newer:
+		if hasCursor {
+			bucketQuery += ` AND bucket >= ?`
+			messageQuery += ` AND message_id > ?`
+			bucketArgs = append(bucketArgs, Bucket(cursor.Time()))
+			cursorArgs = append(cursorArgs, cursor)
+		}
+		bucketQuery += ` ORDER BY bucket ASC`
+		messageQuery += ` ORDER BY message_id ASC`
+	}
+
+	buckets := h.Db.Query(bucketQuery, bucketArgs...).WithContext(ctx).Iter()
+
+	var (
+		msgs   []*messagev1.Message
+		bucket string
+	)
+	// one message past the page tells whether there are more
+	for len(msgs) <= limit && buckets.Scan(&bucket) {
+		args := append([]any{convID, bucket}, cursorArgs...)
+		// replies are skipped rather than filtered by the query, so a bucket
+		// is paged through until the page fills up
+		iter := h.Db.Query(messageQuery, args...).WithContext(ctx).PageSize(limit + 1 - len(msgs)).Iter()
+
+		var row messageRow
+		for len(msgs) <= limit && iter.Scan(row.dest()...) {
+			if row.threadID != (gocql.UUID{}) {
+				continue
+			}
+			msgs = append(msgs, row.toProto(convID))
+		}
+		if err := iter.Close(); err != nil {
+			buckets.Close()
+			return nil, false, status.Errorf(codes.Internal, "failed to list messages: %v", err)
+		}
+	}
+	if err := buckets.Close(); err != nil {
+		return nil, false, status.Errorf(codes.Internal, "failed to list message buckets: %v", err)
+	}
+
+	more := len(msgs) > limit
+	if more {
+		msgs = msgs[:limit]
+	}
+	return msgs, more, nil
+}
+
+// messageColumns are the columns messageRow scans, in order.
+const messageColumns = `message_id, sender_id, body, edited_at, deleted_at, thread_id, reply_count, last_reply_id`
+
+type messageRow struct {
+	id          gocql.UUID
+	senderID    gocql.UUID
+	body        string
+	editedAt    time.Time
+	deletedAt   time.Time
+	threadID    gocql.UUID
+	replyCount  int
+	lastReplyID gocql.UUID
+}
+
+func (r *messageRow) dest() []any {
+	return []any{&r.id, &r.senderID, &r.body, &r.editedAt, &r.deletedAt, &r.threadID, &r.replyCount, &r.lastReplyID}
+}
+
+// toProto converts the row, leaving a deleted message as a tombstone.
+func (r *messageRow) toProto(convID gocql.UUID) *messagev1.Message {
+	msg := &messagev1.Message{
+		Id:             r.id.String(),
+		ConversationId: convID.String(),
+		SenderId:       r.senderID.String(),
+		Body:           r.body,
+		CreatedAt:      timestamppb.New(r.id.Time()),
+	}
+	if !r.editedAt.IsZero() {
+		msg.EditedAt = timestamppb.New(r.editedAt)
+	}
+	if r.threadID != (gocql.UUID{}) {
+		msg.ThreadId = r.threadID.String()
+	}
+	if r.lastReplyID != (gocql.UUID{}) {
+		msg.ReplyCount = uint32(r.replyCount)
+		msg.LastReplyAt = timestamppb.New(r.lastReplyID.Time())
+	}
+	if !r.deletedAt.IsZero() {
+		msg.Body = ""
+		msg.Deleted = true
+		msg.DeletedAt = timestamppb.New(r.deletedAt)
+	}
+	return msg
+}
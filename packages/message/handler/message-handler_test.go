@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/require"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+)
+
+func bodies(msgs []*messagev1.Message) []string {
+	out := make([]string, len(msgs))
+	for i, m := range msgs {
+		out[i] = m.Body
+	}
+	return out
+}
+
+func TestListMessagesAcrossBuckets(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	convID, sender := gocql.TimeUUID(), gocql.TimeUUID()
+	start := time.Now().Add(-72 * time.Hour)
+
+	// two messages a day over three days, with an empty day in between
+	var ids []gocql.UUID
+	for i, offset := range []time.Duration{0, time.Hour, 48 * time.Hour, 49 * time.Hour, 72 * time.Hour, 73 * time.Hour} {
+		id := gocql.UUIDFromTime(start.Add(offset))
+		ids = append(ids, id)
+		require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+			Id:             id.String(),
+			ConversationId: convID.String(),
+			SenderId:       sender.String(),
+			Body:           string(rune('a' + i)),
+		}))
+	}
+
+	msgs, more, err := h.ListMessages(ctx, convID, gocql.UUID{}, handler.Older, 3)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Equal(t, []string{"f", "e", "d"}, bodies(msgs))
+
+	msgs, more, err = h.ListMessages(ctx, convID, ids[3], handler.Older, 3)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, []string{"c", "b", "a"}, bodies(msgs))
+
+	msgs, more, err = h.ListMessages(ctx, convID, ids[0], handler.Newer, 4)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Equal(t, []string{"b", "c", "d", "e"}, bodies(msgs))
+
+	msgs, more, err = h.ListMessages(ctx, convID, ids[4], handler.Newer, 4)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, []string{"f"}, bodies(msgs))
+	require.Equal(t, ids[5].Time().UnixMilli(), msgs[0].CreatedAt.AsTime().UnixMilli())
+}
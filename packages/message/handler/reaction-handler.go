@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recountTimeout bounds repairCount once the request's own deadline is gone.
+const recountTimeout = 5 * time.Second
+
+// --- DB INSERT ---
+// AddReaction records userID reacting to message id with emoji and counts it.
+// changed is false when the reaction was already there, in which case the
+// count is left alone.
+func (h *MessageHandler) AddReaction(ctx context.Context, id, userID gocql.UUID, emoji string, at time.Time) (changed bool, err error) {
+	applied, err := h.Db.Query(
+		`INSERT INTO chat.message_reactions (message_id, user_id, emoji, reacted_at) VALUES (?, ?, ?, ?) IF NOT EXISTS`,
+		id, userID, emoji, at,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to add reaction: %v", err)
+	}
+	if !applied {
+		return false, nil
+	}
+	h.indexReaction(ctx, `INSERT INTO chat.user_reactions (user_id, message_id, emoji, reacted_at) VALUES (?, ?, ?, ?)`, userID, id, emoji, at)
+
+	if err := h.addToCount(ctx, id, emoji, 1); err != nil {
+		h.repairCount(ctx, id, emoji, err)
+	}
+	return true, nil
+}
+
+// --- DB DELETE ---
+// RemoveReaction takes back userID's emoji reaction to message id. changed is
+// false when there was no such reaction.
+func (h *MessageHandler) RemoveReaction(ctx context.Context, id, userID gocql.UUID, emoji string) (changed bool, err error) {
+	applied, err := h.Db.Query(
+		`DELETE FROM chat.message_reactions WHERE message_id = ? AND user_id = ? AND emoji = ? IF EXISTS`,
+		id, userID, emoji,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to remove reaction: %v", err)
+	}
+	if !applied {
+		return false, nil
+	}
+	h.indexReaction(ctx, `DELETE FROM chat.user_reactions WHERE user_id = ? AND message_id = ? AND emoji = ?`, userID, id, emoji)
+
+	if err := h.addToCount(ctx, id, emoji, -1); err != nil {
+		h.repairCount(ctx, id, emoji, err)
+	}
+	return true, nil
+}
+
+// --- DB LIST ---
+// UserReactions returns the emoji userID reacted to message id with.
+func (h *MessageHandler) UserReactions(ctx context.Context, id, userID gocql.UUID) ([]string, error) {
+	iter := h.Db.Query(
+		`SELECT emoji FROM chat.message_reactions WHERE message_id = ? AND user_id = ?`, id, userID,
+	).WithContext(ctx).Iter()
+
+	var (
+		emojis []string
+		emoji  string
+	)
+	for iter.Scan(&emoji) {
+		emojis = append(emojis, emoji)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list reactions: %v", err)
+	}
+	return emojis, nil
+}
+
+// addToCount moves message id's emoji total by delta.
+func (h *MessageHandler) addToCount(ctx context.Context, id gocql.UUID, emoji string, delta int64) error {
+	if err := h.Db.Query(
+		`UPDATE chat.message_reaction_counts SET count = count + ? WHERE message_id = ? AND emoji = ?`,
+		delta, id, emoji,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to count reaction: %v", err)
+	}
+	return nil
+}
+
+// indexReaction mirrors an applied reaction change into user_reactions. The
+// index only feeds data exports, so a failed write is logged, not returned.
+func (h *MessageHandler) indexReaction(ctx context.Context, stmt string, values ...any) {
+	if err := h.Db.Query(stmt, values...).WithContext(ctx).Exec(); err != nil {
+		slog.Warn("failed to index reaction", slog.String("error", err.Error()))
+	}
+}
+
+// RecountReaction sets message id's emoji total back to the number of
+// reactions stored for it. Counters can only be moved, not set, so a change
+// landing between the two reads still leaves it off by that change.
+func (h *MessageHandler) RecountReaction(ctx context.Context, id gocql.UUID, emoji string) error {
+	iter := h.Db.Query(
+		`SELECT emoji FROM chat.message_reactions WHERE message_id = ?`, id,
+	).WithContext(ctx).Iter()
+	var (
+		want int64
+		e    string
+	)
+	for iter.Scan(&e) {
+		if e == emoji {
+			want++
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to list reactions: %v", err)
+	}
+
+	var have int64
+	err := h.Db.Query(
+		`SELECT count FROM chat.message_reaction_counts WHERE message_id = ? AND emoji = ?`, id, emoji,
+	).WithContext(ctx).Scan(&have)
+	if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+		return status.Errorf(codes.Internal, "failed to count reactions: %v", err)
+	}
+	if want == have {
+		return nil
+	}
+	return h.addToCount(ctx, id, emoji, want-have)
+}
+
+// repairCount recounts message id's emoji total after moving it failed with
+// cause. A timed-out counter update may still have been applied, so the
+// reaction itself is kept either way.
+func (h *MessageHandler) repairCount(ctx context.Context, id gocql.UUID, emoji string, cause error) {
+	slog.Warn("failed to count reaction, recounting",
+		slog.String("message_id", id.String()),
+		slog.String("emoji", emoji),
+		slog.String("error", cause.Error()))
+
+	// the request's deadline may be what the update ran into
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recountTimeout)
+	defer cancel()
+	if err := h.RecountReaction(ctx, id, emoji); err != nil {
+		slog.Warn("failed to recount reaction",
+			slog.String("message_id", id.String()),
+			slog.String("emoji", emoji),
+			slog.String("error", err.Error()))
+	}
+}
+
+// --- DB LIST ---
+// Reactions returns the reaction summaries of messages ids by message id,
+// marking the emoji caller reacted with. Messages nobody reacted to are left
+// out.
+func (h *MessageHandler) Reactions(ctx context.Context, ids []gocql.UUID, caller gocql.UUID) (map[string][]*messagev1.ReactionSummary, error) {
+	out := map[string][]*messagev1.ReactionSummary{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	type key struct{ id, emoji string }
+	reacted := map[key]bool{}
+	iter := h.Db.Query(
+		`SELECT message_id, emoji FROM chat.message_reactions WHERE message_id IN ? AND user_id = ?`,
+		ids, caller,
+	).WithContext(ctx).Iter()
+	var (
+		id    gocql.UUID
+		emoji string
+	)
+	for iter.Scan(&id, &emoji) {
+		reacted[key{id.String(), emoji}] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list reactions: %v", err)
+	}
+
+	iter = h.Db.Query(
+		`SELECT message_id, emoji, count FROM chat.message_reaction_counts WHERE message_id IN ?`, ids,
+	).WithContext(ctx).Iter()
+	var count int64
+	for iter.Scan(&id, &emoji, &count) {
+		// removed reactions leave their counters behind at zero
+		if count <= 0 {
+			continue
+		}
+		k := key{id.String(), emoji}
+		out[k.id] = append(out[k.id], &messagev1.ReactionSummary{
+			Emoji:   emoji,
+			Count:   uint32(count),
+			Reacted: reacted[k],
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count reactions: %v", err)
+	}
+
+	for _, summaries := range out {
+		slices.SortFunc(summaries, func(a, b *messagev1.ReactionSummary) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Emoji, b.Emoji))
+		})
+	}
+	return out, nil
+}
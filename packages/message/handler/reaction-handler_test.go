@@ -0,0 +1,87 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/require"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+)
+
+func TestReactionsAreIdempotent(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	msgID, alice, bob := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+
+	changed, err := h.AddReaction(ctx, msgID, alice, "👍", time.Now())
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = h.AddReaction(ctx, msgID, alice, "👍", time.Now())
+	require.NoError(t, err)
+	require.False(t, changed)
+	_, err = h.AddReaction(ctx, msgID, bob, "👍", time.Now())
+	require.NoError(t, err)
+	_, err = h.AddReaction(ctx, msgID, bob, "🎉", time.Now())
+	require.NoError(t, err)
+
+	summaries, err := h.Reactions(ctx, []gocql.UUID{msgID}, alice)
+	require.NoError(t, err)
+	require.Len(t, summaries[msgID.String()], 2)
+	require.Equal(t, &messagev1.ReactionSummary{Emoji: "👍", Count: 2, Reacted: true}, summaries[msgID.String()][0])
+	require.Equal(t, &messagev1.ReactionSummary{Emoji: "🎉", Count: 1}, summaries[msgID.String()][1])
+
+	changed, err = h.RemoveReaction(ctx, msgID, bob, "🎉")
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = h.RemoveReaction(ctx, msgID, bob, "🎉")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	summaries, err = h.Reactions(ctx, []gocql.UUID{msgID}, bob)
+	require.NoError(t, err)
+	require.Len(t, summaries[msgID.String()], 1)
+	require.Equal(t, uint32(2), summaries[msgID.String()][0].GetCount())
+	require.True(t, summaries[msgID.String()][0].GetReacted())
+
+	mine, err := h.UserReactions(ctx, msgID, bob)
+	require.NoError(t, err)
+	require.Equal(t, []string{"👍"}, mine)
+
+	var indexed []string
+	iter := db.Query(`SELECT emoji FROM chat.user_reactions WHERE user_id = ? AND message_id = ?`, bob, msgID).Iter()
+	var emoji string
+	for iter.Scan(&emoji) {
+		indexed = append(indexed, emoji)
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, []string{"👍"}, indexed)
+}
+
+func TestRecountReaction(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	msgID, alice, bob := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	_, err = h.AddReaction(ctx, msgID, alice, "👍", time.Now())
+	require.NoError(t, err)
+	_, err = h.AddReaction(ctx, msgID, bob, "👍", time.Now())
+	require.NoError(t, err)
+
+	// a counter update that timed out but still landed
+	require.NoError(t, db.Query(
+		`UPDATE chat.message_reaction_counts SET count = count + 1 WHERE message_id = ? AND emoji = ?`, msgID, "👍",
+	).Exec())
+
+	require.NoError(t, h.RecountReaction(ctx, msgID, "👍"))
+	summaries, err := h.Reactions(ctx, []gocql.UUID{msgID}, alice)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), summaries[msgID.String()][0].GetCount())
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Cursor is one of a member's receipt cursors.
+type Cursor string
+
+const (
+	ReadCursor      Cursor = "read"
+	DeliveredCursor Cursor = "delivered"
+)
+
+// casAttempts bounds how often AdvanceCursor retries when concurrent updates
+// keep beating it to a first write.
+const casAttempts = 3
+
+// --- DB GET ---
+// MessageExists reports whether message id is stored in conversation convID.
+func (h *MessageHandler) MessageExists(ctx context.Context, convID, id gocql.UUID) (bool, error) {
+	var found gocql.UUID
+	err := h.Db.Query(
+		`SELECT message_id FROM chat.messages WHERE conversation_id = ? AND bucket = ? AND message_id = ?`,
+		convID, Bucket(id.Time()), id,
+	).WithContext(ctx).Scan(&found)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to get message: %v", err)
+	}
+	return true, nil
+}
+
+// --- DB UPDATE ---
+// AdvanceCursor moves userID's cursor in conversation convID to message id,
+// unless it already points at or past it. moved reports whether it changed.
+// The comparison happens in a lightweight transaction, so concurrent calls
+// cannot move a cursor backwards.
+func (h *MessageHandler) AdvanceCursor(ctx context.Context, convID, userID gocql.UUID, cursor Cursor, id gocql.UUID, at time.Time) (moved bool, err error) {
+	column := string(cursor) + "_message_id"
+	update := `UPDATE chat.message_receipts SET ` + column + ` = ?, ` + string(cursor) + `_at = ?
+		 WHERE conversation_id = ? AND user_id = ?`
+
+	for range casAttempts {
+		prev := map[string]any{}
+		applied, err := h.Db.Query(update+` IF `+column+` < ?`,
+			id, at, convID, userID, id,
+		).WithContext(ctx).MapScanCAS(prev)
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "failed to update receipt: %v", err)
+		}
+		if applied {
+			return true, nil
+		}
+		// a set cursor failed the comparison, so it is already further along
+		if current, _ := prev[column].(gocql.UUID); current != (gocql.UUID{}) {
+			return false, nil
+		}
+
+		// the cursor was never set, which no comparison matches
+		applied, err = h.Db.Query(update+` IF `+column+` = null`,
+			id, at, convID, userID,
+		).WithContext(ctx).MapScanCAS(map[string]any{})
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "failed to update receipt: %v", err)
+		}
+		if applied {
+			return true, nil
+		}
+		// another call set it first; compare against what it wrote
+	}
+	return false, status.Error(codes.Aborted, "receipt updated concurrently, try again")
+}
+
+// --- DB GET ---
+// GetReceipt returns userID's receipt in conversation convID; a member with no
+// cursors yet gets an empty one.
+func (h *MessageHandler) GetReceipt(ctx context.Context, convID, userID gocql.UUID) (*messagev1.Receipt, error) {
+	var row receiptRow
+	err := h.Db.Query(
+		`SELECT user_id, read_message_id, read_at, delivered_message_id, delivered_at
+		 FROM chat.message_receipts WHERE conversation_id = ? AND user_id = ?`,
+		convID, userID,
+	).WithContext(ctx).Scan(row.dest()...)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return &messagev1.Receipt{UserId: userID.String()}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get receipt: %v", err)
+	}
+	return row.toProto(), nil
+}
+
+// --- DB LIST ---
+// ListReceipts returns the receipts of conversation convID's members who
+// have any.
+func (h *MessageHandler) ListReceipts(ctx context.Context, convID gocql.UUID) ([]*messagev1.Receipt, error) {
+	iter := h.Db.Query(
+		`SELECT user_id, read_message_id, read_at, delivered_message_id, delivered_at
+		 FROM chat.message_receipts WHERE conversation_id = ?`,
+		convID,
+	).WithContext(ctx).Iter()
+
+	var (
+		receipts []*messagev1.Receipt
+		row      receiptRow
+	)
+	for iter.Scan(row.dest()...) {
+		receipts = append(receipts, row.toProto())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list receipts: %v", err)
+	}
+	return receipts, nil
+}
+
+type receiptRow struct {
+	userID      gocql.UUID
+	readID      gocql.UUID
+	readAt      time.Time
+	deliveredID gocql.UUID
+	deliveredAt time.Time
+}
+
+func (r *receiptRow) dest() []any {
+	return []any{&r.userID, &r.readID, &r.readAt, &r.deliveredID, &r.deliveredAt}
+}
+
+func (r *receiptRow) toProto() *messagev1.Receipt {
+	receipt := &messagev1.Receipt{UserId: r.userID.String()}
+	if r.readID != (gocql.UUID{}) {
+		receipt.ReadMessageId = r.readID.String()
+		receipt.ReadAt = timestamppb.New(r.readAt)
+	}
+	if r.deliveredID != (gocql.UUID{}) {
+		receipt.DeliveredMessageId = r.deliveredID.String()
+		receipt.DeliveredAt = timestamppb.New(r.deliveredAt)
+	}
+	return receipt
+}
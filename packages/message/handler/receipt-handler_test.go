@@ -0,0 +1,49 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/require"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+)
+
+func TestAdvanceCursorOnlyMovesForward(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	convID, user := gocql.TimeUUID(), gocql.TimeUUID()
+	older := gocql.UUIDFromTime(time.Now().Add(-time.Minute))
+	newer := gocql.TimeUUID()
+
+	receipt, err := h.GetReceipt(ctx, convID, user)
+	require.NoError(t, err)
+	require.Empty(t, receipt.GetReadMessageId())
+
+	// the first write sets a cursor that was never set
+	moved, err := h.AdvanceCursor(ctx, convID, user, handler.ReadCursor, newer, time.Now())
+	require.NoError(t, err)
+	require.True(t, moved)
+
+	moved, err = h.AdvanceCursor(ctx, convID, user, handler.ReadCursor, older, time.Now())
+	require.NoError(t, err)
+	require.False(t, moved)
+	moved, err = h.AdvanceCursor(ctx, convID, user, handler.ReadCursor, newer, time.Now())
+	require.NoError(t, err)
+	require.False(t, moved)
+
+	// the other cursor is independent
+	moved, err = h.AdvanceCursor(ctx, convID, user, handler.DeliveredCursor, older, time.Now())
+	require.NoError(t, err)
+	require.True(t, moved)
+
+	receipts, err := h.ListReceipts(ctx, convID)
+	require.NoError(t, err)
+	require.Len(t, receipts, 1)
+	require.Equal(t, newer.String(), receipts[0].GetReadMessageId())
+	require.Equal(t, older.String(), receipts[0].GetDeliveredMessageId())
+}
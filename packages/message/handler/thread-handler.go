@@ -0,0 +1,543 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// touchConcurrency bounds how many followers TouchFollowedThreads updates at
+// once.
+const touchConcurrency = 8
+
+// FollowedThread is a thread a user follows, as kept for listing their
+// threads.
+type FollowedThread struct {
+	ThreadID       gocql.UUID
+	ConversationID gocql.UUID
+	FollowedAt     time.Time
+	// the thread's newest reply and the newest the user has read; zero when
+	// there is none
+	LastReplyID gocql.UUID
+	ReadReplyID gocql.UUID
+}
+
+// ThreadCursor is a place in the threads a user follows, which are listed
+// most recently active first.
+type ThreadCursor struct {
+	LastActive time.Time
+	ThreadID   gocql.UUID
+}
+
+// LastActive is when the thread last got a reply, or was started if it has
+// none.
+func (t *FollowedThread) LastActive() time.Time {
+	if t.LastReplyID != (gocql.UUID{}) {
+		return t.LastReplyID.Time()
+	}
+	return t.ThreadID.Time()
+}
+
+// Unread reports whether the thread has replies after the last one the user
+// read.
+func (t *FollowedThread) Unread() bool {
+	return t.LastReplyID != (gocql.UUID{}) && t.LastReplyID.Time().After(t.ReadReplyID.Time())
+}
+
+// --- DB UPDATE ---
+// CountReply adds reply replyID to the reply count of thread root threadID in
+// conversation convID, and makes it the last reply unless a newer one already
+// is. It returns the count afterwards. The root is compared and set in a
+// lightweight transaction, so concurrent replies are all counted.
+func (h *MessageHandler) CountReply(ctx context.Context, convID, threadID, replyID gocql.UUID) (int, error) {
+	count, last, err := h.threadState(ctx, convID, threadID)
+	if err != nil {
+		return 0, err
+	}
+	update := `UPDATE chat.messages SET reply_count = ?, last_reply_id = ?
+		 WHERE conversation_id = ? AND bucket = ? AND message_id = ?`
+
+	for range casAttempts {
+		next := last
+		if replyID.Time().After(last.Time()) {
+			next = replyID
+		}
+		values := []any{count + 1, next, convID, Bucket(threadID.Time()), threadID}
+		// a root without replies has neither column set, which no comparison
+		// matches
+		stmt := update + ` IF reply_count = null AND last_reply_id = null`
+		if last != (gocql.UUID{}) {
+			stmt = update + ` IF reply_count = ? AND last_reply_id = ?`
+			values = append(values, count, last)
+		}
+
+		prev := map[string]any{}
+		applied, err := h.Db.Query(stmt, values...).WithContext(ctx).MapScanCAS(prev)
+		if err != nil {
+			return 0, status.Errorf(codes.Internal, "failed to count thread reply: %v", err)
+		}
+		if applied {
+			return count + 1, nil
+		}
+		// another reply was counted first; try again on top of it
+		count, _ = prev["reply_count"].(int)
+		last, _ = prev["last_reply_id"].(gocql.UUID)
+	}
+	return 0, status.Error(codes.Aborted, "thread updated concurrently, try again")
+}
+
+// --- DB UPDATE ---
+// UncountReply takes deleted reply replyID out of thread root threadID in
+// conversation convID: it leaves the thread's replies, the reply count drops
+// by one and the newest reply left becomes the last. A root left without
+// replies goes back to having neither column set. As in CountReply, the root
+// is compared and set in a lightweight transaction.
+func (h *MessageHandler) UncountReply(ctx context.Context, convID, threadID, replyID gocql.UUID) error {
+	if err := h.Db.Query(
+		`DELETE FROM chat.thread_replies WHERE thread_id = ? AND message_id = ?`, threadID, replyID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to uncount thread reply: %v", err)
+	}
+	count, last, err := h.threadState(ctx, convID, threadID)
+	if err != nil {
+		return err
+	}
+
+	for range casAttempts {
+		if count == 0 || last == (gocql.UUID{}) {
+			return nil
+		}
+		var newest gocql.UUID
+		err := h.Db.Query(
+			`SELECT message_id FROM chat.thread_replies WHERE thread_id = ? LIMIT 1`, threadID,
+		).WithContext(ctx).Scan(&newest)
+		if err != nil && !errors.Is(err, gocql.ErrNotFound) {
+			return status.Errorf(codes.Internal, "failed to uncount thread reply: %v", err)
+		}
+		// nil leaves the columns null; timeuuid columns reject the zero UUID
+		var nextCount, nextLast any
+		if count > 1 && newest != (gocql.UUID{}) {
+			nextCount, nextLast = count-1, newest
+		}
+
+		prev := map[string]any{}
+		applied, err := h.Db.Query(
+			`UPDATE chat.messages SET reply_count = ?, last_reply_id = ?
+			 WHERE conversation_id = ? AND bucket = ? AND message_id = ?
+			 IF reply_count = ? AND last_reply_id = ?`,
+			nextCount, nextLast, convID, Bucket(threadID.Time()), threadID, count, last,
+		).WithContext(ctx).MapScanCAS(prev)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to uncount thread reply: %v", err)
+		}
+		if applied {
+			return nil
+		}
+		// another reply was counted or taken out first; try again on top of it
+		count, _ = prev["reply_count"].(int)
+		last, _ = prev["last_reply_id"].(gocql.UUID)
+	}
+	return status.Error(codes.Aborted, "thread updated concurrently, try again")
+}
+
+// threadState returns the reply count and last reply of thread root threadID.
+func (h *MessageHandler) threadState(ctx context.Context, convID, threadID gocql.UUID) (count int, last gocql.UUID, err error) {
+	err = h.Db.Query(
+		`SELECT reply_count, last_reply_id FROM chat.messages WHERE conversation_id = ? AND bucket = ? AND message_id = ?`,
+		convID, Bucket(threadID.Time()), threadID,
+	).WithContext(ctx).Scan(&count, &last)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return 0, gocql.UUID{}, status.Error(codes.NotFound, "message not found")
+	}
+	if err != nil {
+		return 0, gocql.UUID{}, status.Errorf(codes.Internal, "failed to get thread: %v", err)
+	}
+	return count, last, nil
+}
+
+// --- DB LIST ---
+// ListThreadReplies returns up to limit replies in thread threadID of
+// conversation convID on the dir side of cursor, the way ListMessages pages
+// through a conversation.
+func (h *MessageHandler) ListThreadReplies(ctx context.Context, convID, threadID, cursor gocql.UUID, dir Direction, limit int) ([]*messagev1.Message, bool, error) {
+	query := `SELECT message_id FROM chat.thread_replies WHERE thread_id = ?`
+	args := []any{threadID}
+
+	hasCursor := cursor != (gocql.UUID{})
+	switch dir {
+	case Older:
+		if hasCursor {
+			query += ` AND message_id < ?`
+			args = append(args, cursor)
+		}
+	case Newer:
+		if hasCursor {
+			query += ` AND message_id > ?`
+			args = append(args, cursor)
+		}
+		query += ` ORDER BY message_id ASC`
+	}
+	// one reply past the page tells whether there are more
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	iter := h.Db.Query(query, args...).WithContext(ctx).Iter()
+	var (
+		ids []gocql.UUID
+		id  gocql.UUID
+	)
+	for iter.Scan(&id) {
+		ids = append(ids, id)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, false, status.Errorf(codes.Internal, "failed to list thread replies: %v", err)
+	}
+	more := len(ids) > limit
+	if more {
+		ids = ids[:limit]
+	}
+
+	// the replies themselves are stored with the conversation's messages
+	found, err := h.GetMessages(ctx, convID, ids)
+	if err != nil {
+		return nil, false, err
+	}
+
+	msgs := make([]*messagev1.Message, 0, len(ids))
+	for _, id := range ids {
+		if msg, ok := found[id]; ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, more, nil
+}
+
+// CountReplies counts the replies in thread threadID after reply after, or
+// all of them for a zero after, stopping at limit.
+func (h *MessageHandler) CountReplies(ctx context.Context, threadID, after gocql.UUID, limit int) (int, error) {
+	query := `SELECT message_id FROM chat.thread_replies WHERE thread_id = ?`
+	args := []any{threadID}
+	if after != (gocql.UUID{}) {
+		query += ` AND message_id > ?`
+		args = append(args, after)
+	}
+	query += ` LIMIT ?`
+	args = append(args, limit)
+
+	iter := h.Db.Query(query, args...).WithContext(ctx).Iter()
+	var (
+		n  int
+		id gocql.UUID
+	)
+	for iter.Scan(&id) {
+		n++
+	}
+	if err := iter.Close(); err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to count thread replies: %v", err)
+	}
+	return n, nil
+}
+
+// --- DB INSERT ---
+// FollowThread makes userID follow thread threadID of conversation convID.
+// With caughtUp the replies so far count as read; without, as for a root's
+// author followed by the first reply, none do. Following a thread again
+// changes nothing.
+func (h *MessageHandler) FollowThread(ctx context.Context, userID, convID, threadID gocql.UUID, caughtUp bool, at time.Time) error {
+	_, last, err := h.threadState(ctx, convID, threadID)
+	if err != nil {
+		return err
+	}
+	// nil leaves the column null; timeuuid columns reject the zero UUID
+	var lastReply, read any
+	if last != (gocql.UUID{}) {
+		lastReply = last
+		if caughtUp {
+			read = last
+		}
+	}
+
+	if err := h.Db.Query(
+		`INSERT INTO chat.thread_followers (thread_id, user_id) VALUES (?, ?)`,
+		threadID, userID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to follow thread: %v", err)
+	}
+
+	// a row without followed_at was left by a reply racing an unfollow, and
+	// is not followed
+	applied, err := h.Db.Query(
+		`UPDATE chat.followed_threads SET conversation_id = ?, followed_at = ?, last_reply_id = ?, read_reply_id = ?
+		 WHERE user_id = ? AND thread_id = ? IF followed_at = null`,
+		convID, at, lastReply, read, userID, threadID,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to follow thread: %v", err)
+	}
+	if !applied {
+		return nil
+	}
+	followed := FollowedThread{ThreadID: threadID, LastReplyID: last}
+	return h.listFollowedThread(ctx, userID, threadID, followed.LastActive())
+}
+
+// --- DB DELETE ---
+// UnfollowThread stops userID following thread threadID.
+func (h *MessageHandler) UnfollowThread(ctx context.Context, userID, threadID gocql.UUID) error {
+	followed, err := h.GetFollowedThread(ctx, userID, threadID)
+	if err != nil {
+		return err
+	}
+	if err := h.Db.Query(
+		`DELETE FROM chat.followed_threads WHERE user_id = ? AND thread_id = ?`,
+		userID, threadID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to unfollow thread: %v", err)
+	}
+	if followed != nil {
+		if err := h.unlistFollowedThread(ctx, userID, threadID, followed.LastActive()); err != nil {
+			return err
+		}
+	}
+	if err := h.Db.Query(
+		`DELETE FROM chat.thread_followers WHERE thread_id = ? AND user_id = ?`,
+		threadID, userID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to unfollow thread: %v", err)
+	}
+	return nil
+}
+
+// --- DB LIST ---
+// ThreadFollowers returns the ids of the users following thread threadID.
+func (h *MessageHandler) ThreadFollowers(ctx context.Context, threadID gocql.UUID) ([]gocql.UUID, error) {
+	iter := h.Db.Query(
+		`SELECT user_id FROM chat.thread_followers WHERE thread_id = ?`, threadID,
+	).WithContext(ctx).Iter()
+
+	var (
+		followers []gocql.UUID
+		userID    gocql.UUID
+	)
+	for iter.Scan(&userID) {
+		followers = append(followers, userID)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list thread followers: %v", err)
+	}
+	return followers, nil
+}
+
+// --- DB UPDATE ---
+// TouchFollowedThreads records reply replyID as the newest reply in thread
+// threadID for each of followers, unless a newer one already is, and moves
+// the thread up their list of threads. Followers are updated independently,
+// so one failing does not hold up the rest; the failures are returned
+// together.
+func (h *MessageHandler) TouchFollowedThreads(ctx context.Context, threadID gocql.UUID, followers []gocql.UUID, replyID gocql.UUID) error {
+	var (
+		g    errgroup.Group
+		mu   sync.Mutex
+		errs []error
+	)
+	g.SetLimit(touchConcurrency)
+	for _, userID := range followers {
+		g.Go(func() error {
+			if err := h.touchFollowedThread(ctx, userID, threadID, replyID); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return errors.Join(errs...)
+}
+
+// touchFollowedThread is TouchFollowedThreads for one follower.
+func (h *MessageHandler) touchFollowedThread(ctx context.Context, userID, threadID, replyID gocql.UUID) error {
+	followed, err := h.GetFollowedThread(ctx, userID, threadID)
+	if err != nil {
+		return err
+	}
+	if followed == nil || !replyID.Time().After(followed.LastActive()) {
+		return nil
+	}
+	prev := followed.LastActive()
+
+	if err := h.Db.Query(
+		`UPDATE chat.followed_threads SET last_reply_id = ? WHERE user_id = ? AND thread_id = ?`,
+		replyID, userID, threadID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to update followed thread: %v", err)
+	}
+	followed.LastReplyID = replyID
+	if err := h.listFollowedThread(ctx, userID, threadID, followed.LastActive()); err != nil {
+		return err
+	}
+	return h.unlistFollowedThread(ctx, userID, threadID, prev)
+}
+
+// listFollowedThread lists thread threadID among userID's threads as last
+// active at lastActive.
+func (h *MessageHandler) listFollowedThread(ctx context.Context, userID, threadID gocql.UUID, lastActive time.Time) error {
+	if err := h.Db.Query(
+		`INSERT INTO chat.followed_threads_by_activity (user_id, last_active, thread_id) VALUES (?, ?, ?)`,
+		userID, lastActive, threadID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to list followed thread: %v", err)
+	}
+	return nil
+}
+
+// unlistFollowedThread removes the listing listFollowedThread made.
+func (h *MessageHandler) unlistFollowedThread(ctx context.Context, userID, threadID gocql.UUID, lastActive time.Time) error {
+	if err := h.Db.Query(
+		`DELETE FROM chat.followed_threads_by_activity WHERE user_id = ? AND last_active = ? AND thread_id = ?`,
+		userID, lastActive, threadID,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to unlist followed thread: %v", err)
+	}
+	return nil
+}
+
+// MarkThreadRead moves userID's read cursor in thread threadID to reply
+// replyID, unless it already points at or past it. moved reports whether it
+// changed. As with AdvanceCursor, the comparison happens in a lightweight
+// transaction so the cursor never moves backwards.
+func (h *MessageHandler) MarkThreadRead(ctx context.Context, userID, threadID, replyID gocql.UUID) (moved bool, err error) {
+	update := `UPDATE chat.followed_threads SET read_reply_id = ? WHERE user_id = ? AND thread_id = ?`
+
+	for range casAttempts {
+		prev := map[string]any{}
+		applied, err := h.Db.Query(update+` IF read_reply_id < ?`,
+			replyID, userID, threadID, replyID,
+		).WithContext(ctx).MapScanCAS(prev)
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "failed to mark thread read: %v", err)
+		}
+		if applied {
+			return true, nil
+		}
+		if current, _ := prev["read_reply_id"].(gocql.UUID); current != (gocql.UUID{}) {
+			return false, nil
+		}
+
+		applied, err = h.Db.Query(update+` IF read_reply_id = null`,
+			replyID, userID, threadID,
+		).WithContext(ctx).MapScanCAS(map[string]any{})
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "failed to mark thread read: %v", err)
+		}
+		if applied {
+			return true, nil
+		}
+	}
+	return false, status.Error(codes.Aborted, "thread read concurrently, try again")
+}
+
+// --- DB GET ---
+// GetFollowedThread returns thread threadID as userID follows it, or nil if
+// they do not.
+func (h *MessageHandler) GetFollowedThread(ctx context.Context, userID, threadID gocql.UUID) (*FollowedThread, error) {
+	var row FollowedThread
+	err := h.Db.Query(
+		`SELECT `+followedThreadColumns+` FROM chat.followed_threads WHERE user_id = ? AND thread_id = ?`,
+		userID, threadID,
+	).WithContext(ctx).Scan(row.dest()...)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get followed thread: %v", err)
+	}
+	if row.FollowedAt.IsZero() {
+		return nil, nil
+	}
+	return &row, nil
+}
+
+// --- DB LIST ---
+// ListFollowedThreads reads the next limit of the threads userID follows
+// after cursor before, most recently active first, or the first limit for a
+// zero before. It returns the ones still followed and the cursor after the
+// last one read; listings left behind by a racing reply or unfollow are
+// read but not returned, so there can be fewer than limit threads with more
+// after them.
+func (h *MessageHandler) ListFollowedThreads(ctx context.Context, userID gocql.UUID, before ThreadCursor, limit int) (threads []*FollowedThread, next ThreadCursor, more bool, err error) {
+	query := `SELECT last_active, thread_id FROM chat.followed_threads_by_activity WHERE user_id = ?`
+	args := []any{userID}
+	if before.ThreadID != (gocql.UUID{}) {
+		query += ` AND (last_active, thread_id) < (?, ?)`
+		args = append(args, before.LastActive, before.ThreadID)
+	}
+	// one listing past the page tells whether there are more
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	iter := h.Db.Query(query, args...).WithContext(ctx).Iter()
+	var (
+		listed []ThreadCursor
+		cur    ThreadCursor
+	)
+	for iter.Scan(&cur.LastActive, &cur.ThreadID) {
+		listed = append(listed, cur)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, ThreadCursor{}, false, status.Errorf(codes.Internal, "failed to list followed threads: %v", err)
+	}
+	more = len(listed) > limit
+	if more {
+		listed = listed[:limit]
+	}
+	if len(listed) == 0 {
+		return nil, ThreadCursor{}, false, nil
+	}
+
+	ids := make([]gocql.UUID, len(listed))
+	for i, l := range listed {
+		ids[i] = l.ThreadID
+	}
+	iter = h.Db.Query(
+		`SELECT `+followedThreadColumns+` FROM chat.followed_threads WHERE user_id = ? AND thread_id IN ?`,
+		userID, ids,
+	).WithContext(ctx).Iter()
+	found := make(map[gocql.UUID]*FollowedThread, len(ids))
+	for {
+		row := &FollowedThread{}
+		if !iter.Scan(row.dest()...) {
+			break
+		}
+		if !row.FollowedAt.IsZero() {
+			found[row.ThreadID] = row
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, ThreadCursor{}, false, status.Errorf(codes.Internal, "failed to list followed threads: %v", err)
+	}
+
+	for _, l := range listed {
+		// timestamps keep milliseconds, so the listing of the thread as it
+		// is now matches to the millisecond
+		t, ok := found[l.ThreadID]
+		if ok && t.LastActive().Truncate(time.Millisecond).Equal(l.LastActive) {
+			threads = append(threads, t)
+		}
+	}
+	return threads, listed[len(listed)-1], more, nil
+}
+
+// followedThreadColumns are the columns FollowedThread.dest scans, in order.
+const followedThreadColumns = `thread_id, conversation_id, followed_at, last_reply_id, read_reply_id`
+
+func (t *FollowedThread) dest() []any {
+	return []any{&t.ThreadID, &t.ConversationID, &t.FollowedAt, &t.LastReplyID, &t.ReadReplyID}
+}
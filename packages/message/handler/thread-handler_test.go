@@ -0,0 +1,177 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/require"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	"github.com/yaninyzwitty/chat/packages/message/handler"
+)
+
+func TestThreadReplies(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	convID, sender := gocql.TimeUUID(), gocql.TimeUUID()
+	rootID := gocql.TimeUUID()
+	require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+		Id:             rootID.String(),
+		ConversationId: convID.String(),
+		SenderId:       sender.String(),
+		Body:           "root",
+	}))
+
+	var replyIDs []gocql.UUID
+	for _, body := range []string{"a", "b", "c"} {
+		id := gocql.TimeUUID()
+		replyIDs = append(replyIDs, id)
+		require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+			Id:             id.String(),
+			ConversationId: convID.String(),
+			SenderId:       sender.String(),
+			Body:           body,
+			ThreadId:       rootID.String(),
+		}))
+		_, err := h.CountReply(ctx, convID, rootID, id)
+		require.NoError(t, err)
+	}
+
+	// replies stay out of the conversation itself
+	msgs, more, err := h.ListMessages(ctx, convID, gocql.UUID{}, handler.Older, 10)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, []string{"root"}, bodies(msgs))
+	require.Equal(t, uint32(3), msgs[0].GetReplyCount())
+	require.Equal(t, replyIDs[2].Time().UnixNano(), msgs[0].GetLastReplyAt().AsTime().UnixNano())
+
+	replies, more, err := h.ListThreadReplies(ctx, convID, rootID, gocql.UUID{}, handler.Older, 2)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Equal(t, []string{"c", "b"}, bodies(replies))
+	require.Equal(t, rootID.String(), replies[0].GetThreadId())
+
+	replies, more, err = h.ListThreadReplies(ctx, convID, rootID, replyIDs[0], handler.Newer, 2)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, []string{"b", "c"}, bodies(replies))
+
+	// deleting the last reply hands its place to the one before
+	_, deleted, err := h.DeleteMessage(ctx, replies[1], sender, time.Now())
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.NoError(t, h.UncountReply(ctx, convID, rootID, replyIDs[2]))
+
+	root, err := h.GetMessage(ctx, convID, rootID)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), root.GetReplyCount())
+	require.Equal(t, replyIDs[1].Time().UnixNano(), root.GetLastReplyAt().AsTime().UnixNano())
+	replies, _, err = h.ListThreadReplies(ctx, convID, rootID, gocql.UUID{}, handler.Older, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, bodies(replies))
+
+	// a root left without replies can be replied to from scratch
+	require.NoError(t, h.UncountReply(ctx, convID, rootID, replyIDs[1]))
+	require.NoError(t, h.UncountReply(ctx, convID, rootID, replyIDs[0]))
+	root, err = h.GetMessage(ctx, convID, rootID)
+	require.NoError(t, err)
+	require.Zero(t, root.GetReplyCount())
+	require.Nil(t, root.GetLastReplyAt())
+	n, err := h.CountReply(ctx, convID, rootID, replyIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestFollowedThreads(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+	h := handler.NewMessageHandler(db)
+
+	convID, author, follower := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	rootID := gocql.TimeUUID()
+	require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+		Id:             rootID.String(),
+		ConversationId: convID.String(),
+		SenderId:       author.String(),
+		Body:           "root",
+	}))
+
+	reply := func(body string) gocql.UUID {
+		id := gocql.TimeUUID()
+		require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+			Id:             id.String(),
+			ConversationId: convID.String(),
+			SenderId:       author.String(),
+			Body:           body,
+			ThreadId:       rootID.String(),
+		}))
+		_, err := h.CountReply(ctx, convID, rootID, id)
+		require.NoError(t, err)
+		followers, err := h.ThreadFollowers(ctx, rootID)
+		require.NoError(t, err)
+		require.NoError(t, h.TouchFollowedThreads(ctx, rootID, followers, id))
+		return id
+	}
+
+	reply("before following")
+	require.NoError(t, h.FollowThread(ctx, follower, convID, rootID, true, time.Now()))
+
+	followed, err := h.GetFollowedThread(ctx, follower, rootID)
+	require.NoError(t, err)
+	require.NotNil(t, followed)
+	require.False(t, followed.Unread())
+
+	reply("one")
+	last := reply("two")
+
+	threads, _, more, err := h.ListFollowedThreads(ctx, follower, handler.ThreadCursor{}, 10)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Len(t, threads, 1)
+	require.Equal(t, last, threads[0].LastReplyID)
+	require.True(t, threads[0].Unread())
+	n, err := h.CountReplies(ctx, rootID, threads[0].ReadReplyID, 100)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	moved, err := h.MarkThreadRead(ctx, follower, rootID, last)
+	require.NoError(t, err)
+	require.True(t, moved)
+	followed, err = h.GetFollowedThread(ctx, follower, rootID)
+	require.NoError(t, err)
+	require.False(t, followed.Unread())
+
+	// a thread started since is more recently active
+	otherID := gocql.TimeUUID()
+	require.NoError(t, h.InsertMessage(ctx, &messagev1.Message{
+		Id:             otherID.String(),
+		ConversationId: convID.String(),
+		SenderId:       author.String(),
+		Body:           "other root",
+	}))
+	require.NoError(t, h.FollowThread(ctx, follower, convID, otherID, true, time.Now()))
+
+	threads, next, more, err := h.ListFollowedThreads(ctx, follower, handler.ThreadCursor{}, 1)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Len(t, threads, 1)
+	require.Equal(t, otherID, threads[0].ThreadID)
+	threads, _, more, err = h.ListFollowedThreads(ctx, follower, next, 1)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Len(t, threads, 1)
+	require.Equal(t, rootID, threads[0].ThreadID)
+
+	require.NoError(t, h.UnfollowThread(ctx, follower, rootID))
+	reply("after unfollowing")
+
+	threads, _, _, err = h.ListFollowedThreads(ctx, follower, handler.ThreadCursor{}, 10)
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	require.Equal(t, otherID, threads[0].ThreadID)
+}
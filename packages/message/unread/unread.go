@@ -0,0 +1,169 @@
+// Package unread keeps each user's unread and mention counts per
+// conversation in Redis, so badge counts are one read away.
+//
+// The counts are a cache: sends increment them, and the truth lives in
+// Cassandra as the messages after each member's read cursor. Every pair a
+// send touches is queued for reconciliation, which recomputes it from
+// Cassandra, so increments that are lost or race a reset settle on the right
+// number soon after.
+package unread
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// dirtyKey is the set of user/conversation pairs whose counts changed since
+// they were last reconciled.
+const dirtyKey = "unread:dirty"
+
+// mentionPattern matches a mention in a message body, <@user-id>.
+var mentionPattern = regexp.MustCompile(`<@([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})>`)
+
+// mentionsSuffix marks the hash field holding a conversation's mention count.
+const mentionsSuffix = ":mentions"
+
+type Counts struct {
+	Unread   int64
+	Mentions int64
+}
+
+// Pair names one user's counts in one conversation.
+type Pair struct {
+	UserID         string
+	ConversationID string
+}
+
+type Store struct {
+	rdb *redis.Client
+}
+
+func NewStore(rdb *redis.Client) *Store {
+	return &Store{rdb: rdb}
+}
+
+// Mentions returns the ids of the users body mentions, each once, in
+// lowercase.
+func Mentions(body string) []string {
+	var ids []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		id := strings.ToLower(m[1])
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// countsKey is a hash of conversation id to unread count, with the mention
+// count under the id plus mentionsSuffix.
+func countsKey(userID string) string { return "unread:{" + userID + "}" }
+
+// Increment counts a new message in convID for each of recipients, and a
+// mention for each of mentioned, which should be recipients too.
+func (s *Store) Increment(ctx context.Context, convID string, recipients, mentioned []string) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	pipe := s.rdb.Pipeline()
+	for _, id := range recipients {
+		pipe.HIncrBy(ctx, countsKey(id), convID, 1)
+		pipe.SAdd(ctx, dirtyKey, id+"/"+convID)
+	}
+	for _, id := range mentioned {
+		pipe.HIncrBy(ctx, countsKey(id), convID+mentionsSuffix, 1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment unread counts: %w", err)
+	}
+	return nil
+}
+
+// Set replaces userID's counts in convID. Zero counts are removed, so the hash
+// only holds conversations with something unread.
+func (s *Store) Set(ctx context.Context, userID, convID string, c Counts) error {
+	key := countsKey(userID)
+	var err error
+	if c.Unread == 0 && c.Mentions == 0 {
+		err = s.rdb.HDel(ctx, key, convID, convID+mentionsSuffix).Err()
+	} else {
+		err = s.rdb.HSet(ctx, key, convID, c.Unread, convID+mentionsSuffix, c.Mentions).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set unread counts: %w", err)
+	}
+	return nil
+}
+
+// Get returns userID's counts by conversation id, leaving out conversations
+// with nothing unread.
+func (s *Store) Get(ctx context.Context, userID string) (map[string]Counts, error) {
+	fields, err := s.rdb.HGetAll(ctx, countsKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+
+	out := map[string]Counts{}
+	for field, value := range fields {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			continue
+		}
+		if convID, ok := strings.CutSuffix(field, mentionsSuffix); ok {
+			c := out[convID]
+			c.Mentions = n
+			out[convID] = c
+			continue
+		}
+		c := out[field]
+		c.Unread = n
+		out[field] = c
+	}
+	// a mention without an unread message is left over from a race
+	for convID, c := range out {
+		if c.Unread == 0 {
+			delete(out, convID)
+		}
+	}
+	return out, nil
+}
+
+// MarkDirty queues pairs for reconciliation, for changes that move counts
+// without a send, and for claimed pairs that failed to reconcile.
+func (s *Store) MarkDirty(ctx context.Context, pairs ...Pair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	members := make([]any, len(pairs))
+	for i, p := range pairs {
+		members[i] = p.UserID + "/" + p.ConversationID
+	}
+	if err := s.rdb.SAdd(ctx, dirtyKey, members...).Err(); err != nil {
+		return fmt.Errorf("failed to queue unread counts: %w", err)
+	}
+	return nil
+}
+
+// ClaimDirty takes up to n pairs off the reconciliation queue. A pair that
+// changes again after being claimed is queued again.
+func (s *Store) ClaimDirty(ctx context.Context, n int64) ([]Pair, error) {
+	members, err := s.rdb.SPopN(ctx, dirtyKey, n).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim unread counts: %w", err)
+	}
+	pairs := make([]Pair, 0, len(members))
+	for _, m := range members {
+		userID, convID, ok := strings.Cut(m, "/")
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, Pair{UserID: userID, ConversationID: convID})
+	}
+	return pairs, nil
+}
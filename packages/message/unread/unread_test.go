@@ -0,0 +1,77 @@
+package unread_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/yaninyzwitty/chat/packages/message/unread"
+)
+
+func newStore(t *testing.T) *unread.Store {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return unread.NewStore(rdb)
+}
+
+func TestIncrementAndReset(t *testing.T) {
+	ctx := context.Background()
+	s := newStore(t)
+
+	require.NoError(t, s.Increment(ctx, "c1", []string{"alice", "bob"}, []string{"bob"}))
+	require.NoError(t, s.Increment(ctx, "c1", []string{"alice", "bob"}, nil))
+	require.NoError(t, s.Increment(ctx, "c2", []string{"bob"}, nil))
+
+	counts, err := s.Get(ctx, "bob")
+	require.NoError(t, err)
+	require.Equal(t, map[string]unread.Counts{
+		"c1": {Unread: 2, Mentions: 1},
+		"c2": {Unread: 1},
+	}, counts)
+
+	require.NoError(t, s.Set(ctx, "bob", "c1", unread.Counts{}))
+	counts, err = s.Get(ctx, "bob")
+	require.NoError(t, err)
+	require.Equal(t, map[string]unread.Counts{"c2": {Unread: 1}}, counts)
+
+	counts, err = s.Get(ctx, "carol")
+	require.NoError(t, err)
+	require.Empty(t, counts)
+}
+
+func TestClaimDirty(t *testing.T) {
+	ctx := context.Background()
+	s := newStore(t)
+
+	require.NoError(t, s.Increment(ctx, "c1", []string{"alice", "bob"}, nil))
+	// a pair touched twice is queued once
+	require.NoError(t, s.Increment(ctx, "c1", []string{"alice"}, nil))
+
+	pairs, err := s.ClaimDirty(ctx, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []unread.Pair{
+		{UserID: "alice", ConversationID: "c1"},
+		{UserID: "bob", ConversationID: "c1"},
+	}, pairs)
+
+	pairs, err = s.ClaimDirty(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pairs)
+
+	// a pair put back is claimed again
+	retry := unread.Pair{UserID: "alice", ConversationID: "c1"}
+	require.NoError(t, s.MarkDirty(ctx, retry))
+	pairs, err = s.ClaimDirty(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, []unread.Pair{retry}, pairs)
+}
+
+func TestMentions(t *testing.T) {
+	const alice = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	require.Equal(t, []string{alice}, unread.Mentions("hi <@"+alice+"> and <@6BA7B810-9DAD-11D1-80B4-00C04FD430C8>"))
+	require.Empty(t, unread.Mentions("mail me at @alice or <@not-an-id>"))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	authjWT "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	database "github.com/yaninyzwitty/chat/packages/db"
+	"github.com/yaninyzwitty/chat/packages/realtime/controller"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/shared/redisclient"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func main() {
+	// Context that cancels on interrupt/terminate signals
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	if err := run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		slog.Error("error running application",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	slog.Info("server stopped cleanly")
+}
+
+func run(ctx context.Context) error {
+	// Parse flags
+	cp := flag.String("config", "config.yaml", "Path to config file")
+	flag.Parse()
+
+	cfg := &config.Config{}
+	if *cp != "" {
+		if err := cfg.LoadConfig(*cp); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	if cfg.Debug {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	}
+
+	addr := fmt.Sprintf(":%d", cfg.MetricsPort5)
+	slog.Info("metrics addr", "val", addr)
+	// Prometheus metrics
+	reg := prometheus.NewRegistry()
+	monitoring.StartPrometheusServer(reg, addr)
+
+	// gRPC server setup
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(authjWT.AuthInterceptor()),
+		grpc.StreamInterceptor(authjWT.StreamAuthInterceptor()),
+	)
+
+	// ✅ Health check registration
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	// Set initial health state to SERVING
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	reflection.Register(grpcServer)
+
+	// start godotenv
+	if err := godotenv.Load(); err != nil {
+		slog.Warn("Failed to load .env")
+	}
+
+	// Create controller with DB + metrics
+	dbToken := os.Getenv("ASTRA_DB_TOKEN")
+	if dbToken == "" {
+		return errors.New("ASTRA_DB_TOKEN environment variable is not set")
+	}
+
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		return errors.New("REDIS_URL environment variable is not set")
+	}
+
+	redisClient, err := redisclient.Connect(redisURL)
+	if err != nil {
+		return fmt.Errorf("failed to create redis client: %w", err)
+	}
+
+	db := database.ConnectAstra(cfg, dbToken)
+
+	realtimeController := controller.NewRealtimeController(cfg, reg, db, redisClient)
+	realtimev1.RegisterRealtimeServiceServer(grpcServer, realtimeController)
+
+	errorGroup, ctx := errgroup.WithContext(ctx)
+
+	// Start gRPC server goroutine
+	errorGroup.Go(func() error {
+		address := fmt.Sprintf(":%d", cfg.RealtimePort)
+
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %q: %w", address, err)
+		}
+
+		slog.Info("starting [gRPC] realtime service",
+			slog.String("address", address),
+		)
+
+		if err := grpcServer.Serve(lis); err != nil {
+			return fmt.Errorf("failed to serve gRPC service: %w", err)
+		}
+		return nil
+	})
+
+	// Shutdown goroutine
+	errorGroup.Go(func() error {
+		<-ctx.Done() // wait for signal
+		slog.Info("shutdown initiated, marking health as NOT_SERVING...")
+		healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+		// Optional: letting clients drain (AWS NLB default 5s deregistration delay)
+		time.Sleep(3 * time.Second)
+
+		slog.Info("shutting down gRPC server gracefully...")
+		// connections never finish on their own; cut off whatever is left so
+		// clients reconnect elsewhere and resume from their last sequence
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(10 * time.Second):
+			grpcServer.Stop()
+		}
+
+		// close DB
+		if db != nil {
+			db.Close()
+			slog.Info("closed Cassandra session")
+		}
+
+		if err := redisClient.Close(); err != nil {
+			slog.Warn("failed to close redis client", "error", err)
+		}
+
+		return ctx.Err()
+	})
+
+	return errorGroup.Wait()
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	conversationhandler "github.com/yaninyzwitty/chat/packages/conversation/handler"
+	"github.com/yaninyzwitty/chat/packages/realtime/session"
+	"github.com/yaninyzwitty/chat/packages/realtime/typing"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type RealtimeController struct {
+	realtimev1.UnimplementedRealtimeServiceServer
+	conversations *conversationhandler.ConversationHandler
+	log           *events.Log
+	typing        *typing.Indicators
+	M             *monitoring.Metrics
+	Config        *config.Config
+}
+
+func NewRealtimeController(cfg *config.Config, reg *prometheus.Registry, db *gocql.Session, rdb *redis.Client) *RealtimeController {
+	conversations := conversationhandler.NewConversationHandler(db)
+	log := events.NewLog(rdb, events.FromConfig(cfg.Events))
+	return &RealtimeController{
+		Config:        cfg,
+		M:             monitoring.NewMetrics(reg),
+		conversations: conversations,
+		log:           log,
+		typing:        typing.New(rdb, log, conversations, typing.FromConfig(cfg.Typing)),
+	}
+}
+
+// --- CONNECT ---
+// Connect serves one realtime connection until the client hangs up. The
+// connection ends when the caller's token expires, so clients reconnect with
+// a fresh one.
+func (c *RealtimeController) Connect(stream grpc.BidiStreamingServer[realtimev1.ClientFrame, realtimev1.ServerFrame]) error {
+	const op = "connect"
+
+	caller, err := callerUUID(stream.Context())
+	if err != nil {
+		return err
+	}
+	var expiresAt time.Time
+	if claims, _ := authjwt.ClaimsFromContext(stream.Context()); claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	// headers go out as soon as the call is accepted, so a gateway can tell
+	// an accepted connection from a rejected one before the first frame
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	err = session.Serve(stream, caller, expiresAt, c.log, c.conversations, c.typing, session.Config{
+		SendBuffer:        c.Config.Realtime.SendBuffer,
+		HeartbeatInterval: time.Duration(c.Config.Realtime.HeartbeatInterval) * time.Second,
+		MaxSubscriptions:  c.Config.Realtime.MaxSubscriptions,
+	})
+	switch status.Code(err) {
+	case codes.Internal:
+		c.observeError(op, "cassandra")
+	case codes.Unavailable:
+		c.observeError(op, "redis")
+	}
+	return err
+}
+
+// observe records how an RPC ended: Unavailable means Redis failed, and
+// Internal that a membership lookup did.
+func (c *RealtimeController) observe(op string, err error, start time.Time) {
+	switch status.Code(err) {
+	case codes.OK:
+		c.observeDuration(op, "redis", start)
+	case codes.Internal:
+		c.observeError(op, "cassandra")
+	case codes.Unavailable:
+		c.observeError(op, "redis")
+	}
+}
+
+func (c *RealtimeController) observeDuration(op, db string, start time.Time) {
+	c.M.Duration.WithLabelValues(op, db).Observe(time.Since(start).Seconds())
+}
+
+func (c *RealtimeController) observeError(op, db string) {
+	c.M.Errors.WithLabelValues(op, db).Inc()
+}
+
+func callerUUID(ctx context.Context) (gocql.UUID, error) {
+	claims, ok := authjwt.ClaimsFromContext(ctx)
+	if !ok || claims.UserID == "" {
+		return gocql.UUID{}, status.Error(codes.Unauthenticated, "missing caller identity")
+	}
+	id, err := gocql.ParseUUID(claims.UserID)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.Unauthenticated, "invalid caller id: %v", err)
+	}
+	return id, nil
+}
+
+// parseUUID validates a UUID request field, naming the field on failure.
+func parseUUID(field, value string) (gocql.UUID, error) {
+	if value == "" {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	id, err := gocql.ParseUUID(value)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+	}
+	return id, nil
+}
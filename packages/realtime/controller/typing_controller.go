@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- START TYPING ---
+func (c *RealtimeController) StartTyping(ctx context.Context, req *realtimev1.StartTypingRequest) (*realtimev1.StartTypingResponse, error) {
+	start := time.Now()
+	const op = "start_typing"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := c.typing.Start(ctx, caller, convID)
+	c.observe(op, err, start)
+	if err != nil {
+		return nil, err
+	}
+	return &realtimev1.StartTypingResponse{ExpiresAt: timestamppb.New(expiresAt)}, nil
+}
+
+// --- STOP TYPING ---
+func (c *RealtimeController) StopTyping(ctx context.Context, req *realtimev1.StopTypingRequest) (*realtimev1.StopTypingResponse, error) {
+	start := time.Now()
+	const op = "stop_typing"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.typing.Stop(ctx, caller, convID)
+	c.observe(op, err, start)
+	if err != nil {
+		return nil, err
+	}
+	return &realtimev1.StopTypingResponse{}, nil
+}
+
+// --- WATCH TYPING ---
+func (c *RealtimeController) WatchTyping(req *realtimev1.WatchTypingRequest, stream grpc.ServerStreamingServer[realtimev1.WatchTypingResponse]) error {
+	const op = "watch_typing"
+	ctx := stream.Context()
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return err
+	}
+	convID, err := parseUUID("conversation_id", req.GetConversationId())
+	if err != nil {
+		return err
+	}
+
+	err = c.typing.Watch(ctx, caller, convID, func(ev *realtimev1.TypingEvent) error {
+		return stream.Send(&realtimev1.WatchTypingResponse{Typing: ev})
+	})
+	// watches last as long as the client stays, so only failures count
+	if err != nil {
+		c.observe(op, err, time.Time{})
+	}
+	return err
+}
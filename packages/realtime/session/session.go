@@ -0,0 +1,357 @@
+// Package session runs one realtime connection: it replays and tails the
+// user's event log, forwards what the connection subscribed to, and answers
+// the client's frames.
+//
+// Everything sent goes through a bounded per-connection queue drained by a
+// single writer. A connection whose queue fills up is closed rather than
+// allowed to hold events in memory; the client reconnects with the last
+// sequence it saw and picks up from the log.
+package session
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultSendBuffer        = 256
+	DefaultHeartbeatInterval = 15 * time.Second
+	DefaultMaxSubscriptions  = 1000
+)
+
+var (
+	ErrSlowConsumer = status.Error(codes.ResourceExhausted, "connection fell behind; reconnect with the last sequence")
+	ErrTokenExpired = status.Error(codes.Unauthenticated, "token expired")
+)
+
+// Stream is the server side of a Connect call.
+type Stream interface {
+	Context() context.Context
+	Send(*realtimev1.ServerFrame) error
+	Recv() (*realtimev1.ClientFrame, error)
+}
+
+// Conversations answers membership questions; the conversation handler
+// implements it.
+type Conversations interface {
+	Member(ctx context.Context, id, userID gocql.UUID) (*conversationv1.Member, error)
+	Members(ctx context.Context, id gocql.UUID) ([]*conversationv1.Member, error)
+}
+
+// Typing records typing indicators; the typing package implements it.
+type Typing interface {
+	Start(ctx context.Context, user, conv gocql.UUID) (time.Time, error)
+	Stop(ctx context.Context, user, conv gocql.UUID) error
+}
+
+type Config struct {
+	SendBuffer        int
+	HeartbeatInterval time.Duration
+	MaxSubscriptions  int
+}
+
+type session struct {
+	stream Stream
+	user   gocql.UUID
+	log    *events.Log
+	convs  Conversations
+	typer  Typing
+	cfg    Config
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	out    chan *realtimev1.ServerFrame
+
+	mu   sync.Mutex
+	subs map[string]bool
+	// last log sequence handled, sent or filtered out
+	seq uint64
+}
+
+// Serve runs a connection for user until the client goes away, the stream
+// fails, the connection falls behind or expiresAt passes. The first frame
+// must be a Hello.
+func Serve(stream Stream, user gocql.UUID, expiresAt time.Time, log *events.Log, convs Conversations, typer Typing, cfg Config) error {
+	if cfg.SendBuffer <= 0 {
+		cfg.SendBuffer = DefaultSendBuffer
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if cfg.MaxSubscriptions <= 0 {
+		cfg.MaxSubscriptions = DefaultMaxSubscriptions
+	}
+
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	defer cancel(nil)
+	s := &session{
+		stream: stream,
+		user:   user,
+		log:    log,
+		convs:  convs,
+		typer:  typer,
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		out:    make(chan *realtimev1.ServerFrame, cfg.SendBuffer),
+		subs:   map[string]bool{},
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hello := first.GetHello()
+	if hello == nil {
+		return status.Error(codes.InvalidArgument, "the first frame must be a hello")
+	}
+
+	from, resumed, err := log.Resume(ctx, user.String(), hello.GetLastSequence())
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to resume: %v", err)
+	}
+	s.seq = from
+	s.send(&realtimev1.ServerFrame{Frame: &realtimev1.ServerFrame_Welcome{
+		Welcome: &realtimev1.Welcome{Sequence: from, Resumed: resumed},
+	}})
+	if err := s.subscribe(hello.GetConversationIds()); err != nil {
+		return err
+	}
+
+	if !expiresAt.IsZero() {
+		timer := time.AfterFunc(time.Until(expiresAt), func() { cancel(ErrTokenExpired) })
+		defer timer.Stop()
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() { s.tail(from) })
+	wg.Go(s.listen)
+	wg.Go(s.heartbeat)
+	// neither Recv nor a Send stuck behind a slow client can be interrupted,
+	// so the reader and writer are not waited for; both return once the call
+	// ends and the stream is torn down
+	go s.write()
+	go s.read()
+
+	wg.Wait()
+	return s.err()
+}
+
+// err turns the reason the session stopped into the call's result.
+func (s *session) err() error {
+	cause := context.Cause(s.ctx)
+	if errors.Is(cause, io.EOF) {
+		return nil
+	}
+	if _, ok := status.FromError(cause); ok {
+		return cause
+	}
+	return status.FromContextError(cause).Err()
+}
+
+// send queues f for the writer, closing the connection when the queue is full.
+func (s *session) send(f *realtimev1.ServerFrame) {
+	select {
+	case s.out <- f:
+	default:
+		s.cancel(ErrSlowConsumer)
+	}
+}
+
+func (s *session) write() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case f := <-s.out:
+			if err := s.stream.Send(f); err != nil {
+				s.cancel(err)
+				return
+			}
+		}
+	}
+}
+
+// tail replays the log after from and then follows it, on the log's shared
+// reader rather than a read of its own.
+func (s *session) tail(from uint64) {
+	err := s.log.Follow(s.ctx, s.user.String(), from, s.deliver)
+	if err != nil && s.ctx.Err() == nil {
+		s.cancel(status.Errorf(codes.Unavailable, "event log unavailable: %v", err))
+	}
+}
+
+// listen forwards ephemeral events.
+func (s *session) listen() {
+	err := s.log.Listen(s.ctx, s.user.String(), s.deliver)
+	if err != nil && s.ctx.Err() == nil {
+		s.cancel(status.Errorf(codes.Unavailable, "event feed unavailable: %v", err))
+	}
+}
+
+func (s *session) heartbeat() {
+	ticker := time.NewTicker(s.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			seq := s.seq
+			s.mu.Unlock()
+			s.send(&realtimev1.ServerFrame{Frame: &realtimev1.ServerFrame_Heartbeat{
+				Heartbeat: &realtimev1.Heartbeat{Sequence: seq, SentAt: timestamppb.New(now)},
+			}})
+		}
+	}
+}
+
+// deliver sends ev if the connection wants it. Membership changes naming the
+// user always go out, so clients learn of conversations they were added to;
+// removal from a conversation also ends its subscription.
+func (s *session) deliver(ev *realtimev1.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ev.Sequence > s.seq {
+		s.seq = ev.Sequence
+	}
+
+	wanted := s.subs[ev.ConversationId]
+	if m := ev.GetMembership(); m != nil && slices.Contains(m.GetUserIds(), s.user.String()) {
+		wanted = true
+		switch m.GetChange() {
+		case realtimev1.MembershipChange_MEMBERSHIP_CHANGE_REMOVED, realtimev1.MembershipChange_MEMBERSHIP_CHANGE_LEFT:
+			delete(s.subs, ev.ConversationId)
+		}
+	}
+	if wanted {
+		s.send(&realtimev1.ServerFrame{Frame: &realtimev1.ServerFrame_Event{Event: ev}})
+	}
+}
+
+// read handles the client's frames until the stream ends.
+func (s *session) read() {
+	for {
+		f, err := s.stream.Recv()
+		if err != nil {
+			s.cancel(err)
+			return
+		}
+
+		switch f := f.GetFrame().(type) {
+		case *realtimev1.ClientFrame_Subscribe:
+			err = s.subscribe(f.Subscribe.GetConversationIds())
+		case *realtimev1.ClientFrame_Unsubscribe:
+			err = s.unsubscribe(f.Unsubscribe.GetConversationIds())
+		case *realtimev1.ClientFrame_Typing:
+			err = s.typing(f.Typing)
+		case *realtimev1.ClientFrame_Ping:
+			s.send(&realtimev1.ServerFrame{Frame: &realtimev1.ServerFrame_Pong{
+				Pong: &realtimev1.Pong{Nonce: f.Ping.GetNonce()},
+			}})
+		case *realtimev1.ClientFrame_Hello:
+			err = status.Error(codes.InvalidArgument, "hello may only be sent once")
+		default:
+			err = status.Error(codes.InvalidArgument, "unknown frame")
+		}
+		if err != nil {
+			s.cancel(err)
+			return
+		}
+	}
+}
+
+// subscribe adds the conversations of ids the user belongs to and reports
+// the resulting subscriptions. Conversations the user is not in are left out.
+func (s *session) subscribe(ids []string) error {
+	for _, id := range ids {
+		convID, err := gocql.ParseUUID(id)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid conversation_ids: %v", err)
+		}
+
+		s.mu.Lock()
+		known, full := s.subs[id], len(s.subs) >= s.cfg.MaxSubscriptions
+		s.mu.Unlock()
+		if known {
+			continue
+		}
+		if full {
+			return status.Errorf(codes.InvalidArgument, "a connection may subscribe to at most %d conversations", s.cfg.MaxSubscriptions)
+		}
+
+		_, err = s.convs.Member(s.ctx, convID, s.user)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.subs[id] = true
+		s.mu.Unlock()
+	}
+	s.subscribed()
+	return nil
+}
+
+func (s *session) unsubscribe(ids []string) error {
+	s.mu.Lock()
+	for _, id := range ids {
+		delete(s.subs, id)
+	}
+	s.mu.Unlock()
+	s.subscribed()
+	return nil
+}
+
+func (s *session) subscribed() {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.subs))
+	for id := range s.subs {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	s.send(&realtimev1.ServerFrame{Frame: &realtimev1.ServerFrame_Subscribed{
+		Subscribed: &realtimev1.Subscribed{ConversationIds: ids},
+	}})
+}
+
+// typing starts or stops the user's typing indicator, the same as the
+// StartTyping and StopTyping calls. Frames for conversations the connection
+// is not subscribed to are ignored, as are frames over the rate limit.
+func (s *session) typing(t *realtimev1.Typing) error {
+	s.mu.Lock()
+	subscribed := s.subs[t.GetConversationId()]
+	s.mu.Unlock()
+	if !subscribed {
+		return nil
+	}
+
+	convID, _ := gocql.ParseUUID(t.GetConversationId())
+	var err error
+	if t.GetTyping() {
+		_, err = s.typer.Start(s.ctx, s.user, convID)
+	} else {
+		err = s.typer.Stop(s.ctx, s.user, convID)
+	}
+	if status.Code(err) == codes.ResourceExhausted || status.Code(err) == codes.NotFound {
+		return nil
+	}
+	return err
+}
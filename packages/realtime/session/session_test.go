@@ -0,0 +1,197 @@
+package session_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gocql/gocql"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	messagev1 "github.com/yaninyzwitty/chat/gen/message/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/realtime/session"
+	"github.com/yaninyzwitty/chat/packages/realtime/typing"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream hands the session frames from in and collects what it sends.
+type fakeStream struct {
+	ctx  context.Context
+	in   chan *realtimev1.ClientFrame
+	out  chan *realtimev1.ServerFrame
+	hold chan struct{}
+}
+
+func newStream(ctx context.Context) *fakeStream {
+	return &fakeStream{ctx: ctx, in: make(chan *realtimev1.ClientFrame, 8), out: make(chan *realtimev1.ServerFrame, 64)}
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) Send(frame *realtimev1.ServerFrame) error {
+	if f.hold != nil {
+		<-f.hold
+	}
+	f.out <- frame
+	return nil
+}
+
+func (f *fakeStream) Recv() (*realtimev1.ClientFrame, error) {
+	select {
+	case frame, ok := <-f.in:
+		if !ok {
+			return nil, io.EOF
+		}
+		return frame, nil
+	case <-f.ctx.Done():
+		return nil, f.ctx.Err()
+	}
+}
+
+// next returns the next frame sent, skipping heartbeats.
+func (f *fakeStream) next(t *testing.T) *realtimev1.ServerFrame {
+	t.Helper()
+	for {
+		select {
+		case frame := <-f.out:
+			if frame.GetHeartbeat() == nil {
+				return frame
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("no frame sent")
+		}
+	}
+}
+
+// members is a membership table keyed by conversation id.
+type members map[gocql.UUID][]gocql.UUID
+
+func (m members) Member(_ context.Context, id, userID gocql.UUID) (*conversationv1.Member, error) {
+	for _, u := range m[id] {
+		if u == userID {
+			return &conversationv1.Member{UserId: u.String()}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "not a member of this conversation")
+}
+
+func (m members) Members(_ context.Context, id gocql.UUID) ([]*conversationv1.Member, error) {
+	var out []*conversationv1.Member
+	for _, u := range m[id] {
+		out = append(out, &conversationv1.Member{UserId: u.String()})
+	}
+	return out, nil
+}
+
+func newLog(t *testing.T) (*events.Log, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return events.NewLog(rdb, events.Config{}), rdb
+}
+
+func hello(last uint64, convs ...gocql.UUID) *realtimev1.ClientFrame {
+	ids := make([]string, len(convs))
+	for i, c := range convs {
+		ids[i] = c.String()
+	}
+	return &realtimev1.ClientFrame{Frame: &realtimev1.ClientFrame_Hello{
+		Hello: &realtimev1.Hello{LastSequence: last, ConversationIds: ids},
+	}}
+}
+
+func messageEvent(conv gocql.UUID, body string) *realtimev1.Event {
+	return &realtimev1.Event{
+		ConversationId: conv.String(),
+		Payload:        &realtimev1.Event_MessageCreated{MessageCreated: &messagev1.Message{Body: body}},
+	}
+}
+
+func TestResumeAndFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log, rdb := newLog(t)
+
+	alice := gocql.TimeUUID()
+	mine, other, foreign := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	convs := members{mine: {alice}, other: {alice}}
+
+	require.NoError(t, log.Append(ctx, messageEvent(mine, "seen"), alice.String()))
+	require.NoError(t, log.Append(ctx, messageEvent(other, "unsubscribed"), alice.String()))
+	require.NoError(t, log.Append(ctx, messageEvent(mine, "missed"), alice.String()))
+
+	stream := newStream(ctx)
+	stream.in <- hello(1, mine, foreign)
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Serve(stream, alice, time.Time{}, log, convs, typing.New(rdb, log, convs, typing.Config{}), session.Config{})
+	}()
+
+	welcome := stream.next(t).GetWelcome()
+	require.True(t, welcome.GetResumed())
+	require.Equal(t, uint64(1), welcome.GetSequence())
+
+	// the conversation alice is not in is left out
+	require.Equal(t, []string{mine.String()}, stream.next(t).GetSubscribed().GetConversationIds())
+
+	ev := stream.next(t).GetEvent()
+	require.Equal(t, "missed", ev.GetMessageCreated().GetBody())
+	require.Equal(t, uint64(3), ev.GetSequence())
+
+	// events arriving later are tailed
+	require.NoError(t, log.Append(ctx, messageEvent(mine, "live"), alice.String()))
+	require.Equal(t, "live", stream.next(t).GetEvent().GetMessageCreated().GetBody())
+
+	stream.in <- &realtimev1.ClientFrame{Frame: &realtimev1.ClientFrame_Ping{Ping: &realtimev1.Ping{Nonce: 7}}}
+	require.Equal(t, uint64(7), stream.next(t).GetPong().GetNonce())
+
+	close(stream.in)
+	require.NoError(t, <-done)
+}
+
+func TestSlowConsumerIsDisconnected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log, rdb := newLog(t)
+
+	alice, conv := gocql.TimeUUID(), gocql.TimeUUID()
+	convs := members{conv: {alice}}
+	stream := newStream(ctx)
+	stream.hold = make(chan struct{})
+	stream.in <- hello(0, conv)
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Serve(stream, alice, time.Time{}, log, convs, typing.New(rdb, log, convs, typing.Config{}), session.Config{SendBuffer: 4})
+	}()
+
+	// the client never reads, so the queue fills up
+	require.Eventually(t, func() bool {
+		_ = log.Append(ctx, messageEvent(conv, "m"), alice.String())
+		select {
+		case err := <-done:
+			require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, time.Millisecond)
+	close(stream.hold)
+}
+
+func TestTokenExpiry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := newStream(ctx)
+	stream.in <- hello(0)
+	log, rdb := newLog(t)
+	err := session.Serve(stream, gocql.TimeUUID(), time.Now().Add(50*time.Millisecond), log, members{}, typing.New(rdb, log, members{}, typing.Config{}), session.Config{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,305 @@
+// Package typing tracks who is typing in each conversation. Indicators live
+// only in Redis: a sorted set per conversation of the users typing, scored by
+// when each indicator lapses. A user who stops refreshing drops out once the
+// TTL passes, so a client that vanishes mid-sentence does not show as typing
+// for good.
+//
+// Changes go out twice over the event log's pubsub.Broker: on the
+// conversation's topic for WatchTyping, and to the other members' event feeds
+// for realtime connections. Neither reaches Cassandra or the event log.
+package typing
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/redis/go-redis/v9"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"github.com/yaninyzwitty/chat/packages/shared/pubsub"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultTTL        = 6 * time.Second
+	DefaultRateLimit  = 20
+	DefaultRateWindow = 10 * time.Second
+
+	// watchBuffer is how many changes a watch may fall behind before new
+	// ones are dropped; a dropped change lapses or is refreshed within TTL
+	watchBuffer = 64
+)
+
+var ErrRateLimited = status.Error(codes.ResourceExhausted, "typing updates sent too often")
+
+// rateScript counts a call in a rate window and starts the window if it has
+// no expiry yet, in one step, so a count is never left behind for good.
+var rateScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// Conversations lists a conversation's members; the conversation handler
+// implements it.
+type Conversations interface {
+	Members(ctx context.Context, id gocql.UUID) ([]*conversationv1.Member, error)
+}
+
+type Config struct {
+	TTL time.Duration
+	// Start calls allowed per user in each RateWindow
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// FromConfig converts the typing section of the service config.
+func FromConfig(cfg config.TypingConfig) Config {
+	return Config{
+		TTL:        time.Duration(cfg.TTL) * time.Second,
+		RateLimit:  cfg.RateLimit,
+		RateWindow: time.Duration(cfg.RateWindow) * time.Second,
+	}
+}
+
+type Indicators struct {
+	rdb   *redis.Client
+	log   *events.Log
+	convs Conversations
+	cfg   Config
+}
+
+// New returns Indicators on rdb, using the defaults for unset config.
+func New(rdb *redis.Client, log *events.Log, convs Conversations, cfg Config) *Indicators {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = DefaultRateLimit
+	}
+	if cfg.RateWindow <= 0 {
+		cfg.RateWindow = DefaultRateWindow
+	}
+	return &Indicators{rdb: rdb, log: log, convs: convs, cfg: cfg}
+}
+
+// the conversation id is hash tagged so its keys share a cluster slot
+func typingKey(convID string) string { return "typing:{" + convID + "}" }
+func rateKey(userID string) string   { return "typing:rate:" + userID }
+
+// Start shows user as typing in conv until the returned time, telling the
+// other members. Each call extends the indicator.
+func (t *Indicators) Start(ctx context.Context, user, conv gocql.UUID) (time.Time, error) {
+	others, err := t.others(ctx, user, conv)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := t.allow(ctx, user.String()); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(t.cfg.TTL)
+	key := typingKey(conv.String())
+	pipe := t.rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(expiresAt.UnixMilli()), Member: user.String()})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+	pipe.PExpire(ctx, key, t.cfg.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return time.Time{}, status.Errorf(codes.Unavailable, "failed to record typing: %v", err)
+	}
+
+	err = t.publish(ctx, conv, &realtimev1.TypingEvent{
+		UserId:    user.String(),
+		Typing:    true,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, others)
+	return expiresAt, err
+}
+
+// Stop clears user's indicator in conv. Stopping when not typing is a no-op,
+// so it is not rate limited.
+func (t *Indicators) Stop(ctx context.Context, user, conv gocql.UUID) error {
+	others, err := t.others(ctx, user, conv)
+	if err != nil {
+		return err
+	}
+
+	removed, err := t.rdb.ZRem(ctx, typingKey(conv.String()), user.String()).Result()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to record typing: %v", err)
+	}
+	if removed == 0 {
+		return nil
+	}
+	return t.publish(ctx, conv, &realtimev1.TypingEvent{UserId: user.String()}, others)
+}
+
+// Watch calls send with each typing change in conv other than user's own,
+// starting with everyone typing now, until ctx is done. Membership is checked
+// once, when the watch starts.
+func (t *Indicators) Watch(ctx context.Context, user, conv gocql.UUID, send func(*realtimev1.TypingEvent) error) error {
+	if _, err := t.others(ctx, user, conv); err != nil {
+		return err
+	}
+
+	// subscribe before taking the snapshot so little can fall in between;
+	// a change that does is corrected by the next refresh or lapses
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	msgs := make(chan *pubsub.Message, watchBuffer)
+	subscribed := make(chan error, 1)
+	go func() {
+		subscribed <- t.log.Live().Subscribe(ctx, pubsub.ConversationTopic(conv.String()), "", "", func(_ context.Context, msg *pubsub.Message) error {
+			select {
+			case msgs <- msg:
+			default:
+				slog.Warn("dropping typing change for a slow watch", "conversation_id", conv.String())
+			}
+			return nil
+		})
+	}()
+
+	now := time.Now()
+	typing, err := t.rdb.ZRangeByScoreWithScores(ctx, typingKey(conv.String()), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(now.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to read typing: %v", err)
+	}
+
+	// when each user shown as typing lapses, for those whose client never
+	// says they stopped
+	expiry := map[string]time.Time{}
+	for _, z := range typing {
+		id, _ := z.Member.(string)
+		if id == user.String() {
+			continue
+		}
+		expiresAt := time.UnixMilli(int64(z.Score))
+		expiry[id] = expiresAt
+		if err := send(&realtimev1.TypingEvent{UserId: id, Typing: true, ExpiresAt: timestamppb.New(expiresAt)}); err != nil {
+			return err
+		}
+	}
+
+	timer := time.NewTimer(t.cfg.TTL)
+	defer timer.Stop()
+	for {
+		timer.Reset(nextExpiry(expiry, t.cfg.TTL))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-timer.C:
+			for id, expiresAt := range expiry {
+				if now.Before(expiresAt) {
+					continue
+				}
+				delete(expiry, id)
+				if err := send(&realtimev1.TypingEvent{UserId: id}); err != nil {
+					return err
+				}
+			}
+		case err := <-subscribed:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return status.Errorf(codes.Unavailable, "typing subscription failed: %v", err)
+		case msg := <-msgs:
+			ev := &realtimev1.TypingEvent{}
+			if err := proto.Unmarshal(msg.Data, ev); err != nil {
+				slog.Warn("dropping malformed typing event", "error", err)
+				continue
+			}
+			if ev.GetUserId() == user.String() {
+				continue
+			}
+			if ev.GetTyping() {
+				expiry[ev.GetUserId()] = ev.GetExpiresAt().AsTime()
+			} else {
+				delete(expiry, ev.GetUserId())
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// nextExpiry returns how long until the first indicator in expiry lapses, or
+// idle when none is shown.
+func nextExpiry(expiry map[string]time.Time, idle time.Duration) time.Duration {
+	wait := idle
+	for _, expiresAt := range expiry {
+		wait = min(wait, time.Until(expiresAt))
+	}
+	return max(wait, 0)
+}
+
+// others returns the members of conv other than user, failing if user is not
+// one of them.
+func (t *Indicators) others(ctx context.Context, user, conv gocql.UUID) ([]string, error) {
+	members, err := t.convs.Members(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+	var others []string
+	member := false
+	for _, m := range members {
+		if m.GetUserId() == user.String() {
+			member = true
+			continue
+		}
+		others = append(others, m.GetUserId())
+	}
+	if !member {
+		return nil, status.Error(codes.NotFound, "conversation not found")
+	}
+	return others, nil
+}
+
+// allow counts a Start call against userID's window.
+func (t *Indicators) allow(ctx context.Context, userID string) error {
+	n, err := rateScript.Run(ctx, t.rdb, []string{rateKey(userID)}, t.cfg.RateWindow.Milliseconds()).Int64()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to check typing rate: %v", err)
+	}
+	if n > int64(t.cfg.RateLimit) {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// publish sends ev to the conversation's watchers and to the others' event
+// feeds.
+func (t *Indicators) publish(ctx context.Context, conv gocql.UUID, ev *realtimev1.TypingEvent, others []string) error {
+	data, err := proto.Marshal(ev)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode typing event: %v", err)
+	}
+	if err := t.log.Live().Publish(ctx, data, pubsub.ConversationTopic(conv.String())); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to publish typing: %v", err)
+	}
+
+	err = t.log.Publish(ctx, &realtimev1.Event{
+		ConversationId: conv.String(),
+		OccurredAt:     timestamppb.Now(),
+		Payload:        &realtimev1.Event_Typing{Typing: ev},
+	}, others...)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to publish typing: %v", err)
+	}
+	return nil
+}
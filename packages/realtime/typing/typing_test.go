@@ -0,0 +1,166 @@
+package typing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gocql/gocql"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	conversationv1 "github.com/yaninyzwitty/chat/gen/conversation/v1"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/realtime/typing"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// members is a membership table keyed by conversation id.
+type members map[gocql.UUID][]gocql.UUID
+
+func (m members) Members(_ context.Context, id gocql.UUID) ([]*conversationv1.Member, error) {
+	var out []*conversationv1.Member
+	for _, u := range m[id] {
+		out = append(out, &conversationv1.Member{UserId: u.String()})
+	}
+	return out, nil
+}
+
+func newIndicators(t *testing.T, convs members, cfg typing.Config) (*typing.Indicators, *events.Log) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	log := events.NewLog(rdb, events.Config{})
+	return typing.New(rdb, log, convs, cfg), log
+}
+
+// watch starts a watch and returns the events it sends.
+func watch(t *testing.T, ctx context.Context, ind *typing.Indicators, user, conv gocql.UUID) <-chan *realtimev1.TypingEvent {
+	t.Helper()
+	out := make(chan *realtimev1.TypingEvent, 16)
+	go func() {
+		_ = ind.Watch(ctx, user, conv, func(ev *realtimev1.TypingEvent) error {
+			out <- ev
+			return nil
+		})
+	}()
+	// let the watch subscribe
+	time.Sleep(50 * time.Millisecond)
+	return out
+}
+
+func next(t *testing.T, ch <-chan *realtimev1.TypingEvent) *realtimev1.TypingEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("no typing event")
+		return nil
+	}
+}
+
+func TestStartStopAndExpiry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alice, bob, conv := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	ind, _ := newIndicators(t, members{conv: {alice, bob}}, typing.Config{TTL: 200 * time.Millisecond})
+
+	// bob's own indicator is already showing when alice starts watching
+	_, err := ind.Start(ctx, bob, conv)
+	require.NoError(t, err)
+	evs := watch(t, ctx, ind, alice, conv)
+	ev := next(t, evs)
+	require.Equal(t, bob.String(), ev.GetUserId())
+	require.True(t, ev.GetTyping())
+
+	require.NoError(t, ind.Stop(ctx, bob, conv))
+	require.False(t, next(t, evs).GetTyping())
+	// stopping again changes nothing and sends nothing
+	require.NoError(t, ind.Stop(ctx, bob, conv))
+
+	// a client that never stops is reported stopped once the TTL passes
+	_, err = ind.Start(ctx, bob, conv)
+	require.NoError(t, err)
+	require.True(t, next(t, evs).GetTyping())
+	started := time.Now()
+	ev = next(t, evs)
+	require.False(t, ev.GetTyping())
+	require.GreaterOrEqual(t, time.Since(started), 100*time.Millisecond)
+
+	// the watcher's own typing is not echoed back
+	_, err = ind.Start(ctx, alice, conv)
+	require.NoError(t, err)
+	select {
+	case ev := <-evs:
+		require.NotEqual(t, alice.String(), ev.GetUserId())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTypingReachesEventFeeds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alice, bob, conv := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	ind, log := newIndicators(t, members{conv: {alice, bob}}, typing.Config{})
+
+	got := make(chan *realtimev1.Event, 1)
+	go func() { _ = log.Listen(ctx, alice.String(), func(ev *realtimev1.Event) { got <- ev }) }()
+	time.Sleep(50 * time.Millisecond)
+
+	_, err := ind.Start(ctx, bob, conv)
+	require.NoError(t, err)
+	select {
+	case ev := <-got:
+		require.Equal(t, conv.String(), ev.GetConversationId())
+		require.Equal(t, bob.String(), ev.GetTyping().GetUserId())
+		require.NotNil(t, ev.GetTyping().GetExpiresAt())
+	case <-time.After(2 * time.Second):
+		t.Fatal("typing event not published")
+	}
+
+	// nothing is kept in the log
+	head, err := log.Head(ctx, alice.String())
+	require.NoError(t, err)
+	require.Zero(t, head)
+}
+
+func TestRateLimitAndMembership(t *testing.T) {
+	ctx := context.Background()
+	alice, conv := gocql.TimeUUID(), gocql.TimeUUID()
+	ind, _ := newIndicators(t, members{conv: {alice}}, typing.Config{RateLimit: 2})
+
+	for range 2 {
+		_, err := ind.Start(ctx, alice, conv)
+		require.NoError(t, err)
+	}
+	_, err := ind.Start(ctx, alice, conv)
+	require.ErrorIs(t, err, typing.ErrRateLimited)
+
+	_, err = ind.Start(ctx, gocql.TimeUUID(), conv)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRateWindowAlwaysLapses(t *testing.T) {
+	ctx := context.Background()
+	alice, conv := gocql.TimeUUID(), gocql.TimeUUID()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	ind := typing.New(rdb, events.NewLog(rdb, events.Config{}), members{conv: {alice}}, typing.Config{RateLimit: 1, RateWindow: time.Second})
+
+	// a count left without an expiry gets one on the next call
+	require.NoError(t, mr.Set("typing:rate:"+alice.String(), "5"))
+	_, err := ind.Start(ctx, alice, conv)
+	require.ErrorIs(t, err, typing.ErrRateLimited)
+	require.Equal(t, time.Second, mr.TTL("typing:rate:"+alice.String()))
+
+	mr.FastForward(time.Second)
+	_, err = ind.Start(ctx, alice, conv)
+	require.NoError(t, err)
+}
@@ -0,0 +1,106 @@
+// Package blocklist answers whether users have blocked or muted each other. It
+// reads Cassandra directly so other services can run the check on their own
+// session without a round trip through UserService.
+package blocklist
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+	"golang.org/x/sync/errgroup"
+)
+
+// IsBlocked reports whether a has blocked b or b has blocked a.
+func IsBlocked(ctx context.Context, db *gocql.Session, a, b gocql.UUID) (bool, error) {
+	var aBlockedB, bBlockedA bool
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		aBlockedB, err = HasBlocked(gctx, db, a, b)
+		return err
+	})
+	g.Go(func() (err error) {
+		bBlockedA, err = HasBlocked(gctx, db, b, a)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return false, err
+	}
+
+	return aBlockedB || bBlockedA, nil
+}
+
+// HasBlocked reports whether blocker has blocked blocked.
+func HasBlocked(ctx context.Context, db *gocql.Session, blocker, blocked gocql.UUID) (bool, error) {
+	return exists(ctx, db,
+		`SELECT blocked_id FROM chat.user_blocks WHERE user_id = ? AND blocked_id = ?`, blocker, blocked)
+}
+
+// IsMuted reports whether user currently has other muted.
+func IsMuted(ctx context.Context, db *gocql.Session, user, other gocql.UUID) (bool, error) {
+	return exists(ctx, db,
+		`SELECT muted_id FROM chat.user_mutes WHERE user_id = ? AND muted_id = ?`, user, other)
+}
+
+// MutedBy returns the subset of users that currently have other muted, using
+// one query.
+func MutedBy(ctx context.Context, db *gocql.Session, other gocql.UUID, users []gocql.UUID) (map[gocql.UUID]bool, error) {
+	return collect(ctx, db,
+		`SELECT user_id FROM chat.user_mutes WHERE user_id IN ? AND muted_id = ?`, users, other)
+}
+
+// Muted returns the users user currently has muted.
+func Muted(ctx context.Context, db *gocql.Session, user gocql.UUID) (map[gocql.UUID]bool, error) {
+	return collect(ctx, db, `SELECT muted_id FROM chat.user_mutes WHERE user_id = ?`, user)
+}
+
+// Blocked returns the subset of others that viewer has blocked or that have
+// blocked viewer, using one query per direction.
+func Blocked(ctx context.Context, db *gocql.Session, viewer gocql.UUID, others []gocql.UUID) (map[gocql.UUID]bool, error) {
+	out := make(map[gocql.UUID]bool)
+	if len(others) == 0 {
+		return out, nil
+	}
+
+	for _, stmt := range []string{
+		`SELECT blocked_id FROM chat.user_blocks WHERE user_id = ? AND blocked_id IN ?`,
+		`SELECT blocker_id FROM chat.user_blocked_by WHERE user_id = ? AND blocker_id IN ?`,
+	} {
+		iter := db.Query(stmt, viewer, others).WithContext(ctx).Consistency(gocql.One).Iter()
+		var id gocql.UUID
+		for iter.Scan(&id) {
+			out[id] = true
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to query blocks: %w", err)
+		}
+	}
+
+	return out, nil
+}
+
+func collect(ctx context.Context, db *gocql.Session, stmt string, values ...any) (map[gocql.UUID]bool, error) {
+	out := make(map[gocql.UUID]bool)
+	iter := db.Query(stmt, values...).WithContext(ctx).Consistency(gocql.One).Iter()
+	var id gocql.UUID
+	for iter.Scan(&id) {
+		out[id] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to query mutes: %w", err)
+	}
+	return out, nil
+}
+
+func exists(ctx context.Context, db *gocql.Session, stmt string, values ...any) (bool, error) {
+	var id gocql.UUID
+	err := db.Query(stmt, values...).WithContext(ctx).Consistency(gocql.One).Scan(&id)
+	if err == gocql.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query blocklist: %w", err)
+	}
+	return true, nil
+}
@@ -8,14 +8,36 @@ import (
 )
 
 type Config struct {
-	Debug          bool           `yaml:"debug"`
-	AuthPort       int            `yaml:"authPort"`
-	AuthClientPort int            `yaml:"authClientPort"`
-	UserClientPort int            `yaml:"userClientPort"`
-	UserPort       int            `yaml:"userPort"`
-	MetricsPort1   int            `yaml:"metricsPort1"`
-	MetricsPort2   int            `yaml:"metricsPort2"`
+	Debug          bool `yaml:"debug"`
+	AuthPort       int  `yaml:"authPort"`
+	AuthClientPort int  `yaml:"authClientPort"`
+	UserClientPort int  `yaml:"userClientPort"`
+	UserPort       int  `yaml:"userPort"`
+	MetricsPort1   int  `yaml:"metricsPort1"`
+	MetricsPort2   int  `yaml:"metricsPort2"`
+	MetricsPort3   int  `yaml:"metricsPort3"`
+	MetricsPort4   int  `yaml:"metricsPort4"`
+	MetricsPort5   int  `yaml:"metricsPort5"`
+	// ConversationPort serves conversation.v1.ConversationService
+	ConversationPort int `yaml:"conversationPort"`
+	// MessagePort serves message.v1.MessageService
+	MessagePort int `yaml:"messagePort"`
+	// RealtimePort serves realtime.v1.RealtimeService
+	RealtimePort   int            `yaml:"realtimePort"`
 	DatabaseConfig DatabaseConfig `yaml:"db"`
+	User           UserConfig     `yaml:"user"`
+	Avatar         AvatarConfig   `yaml:"avatar"`
+	Presence       PresenceConfig `yaml:"presence"`
+	Settings       SettingsConfig `yaml:"settings"`
+	UserCache      CacheConfig    `yaml:"userCache"`
+
+	Conversation ConversationConfig `yaml:"conversation"`
+	Message      MessageConfig      `yaml:"message"`
+	Events       EventsConfig       `yaml:"events"`
+	Realtime     RealtimeConfig     `yaml:"realtime"`
+	Typing       TypingConfig       `yaml:"typing"`
+	Unread       UnreadConfig       `yaml:"unread"`
+	Reactions    ReactionsConfig    `yaml:"reactions"`
 }
 
 type DatabaseConfig struct {
@@ -27,6 +49,131 @@ type DatabaseConfig struct {
 	LocalDBPort int    `yaml:"localDBPort"`
 }
 
+type UserConfig struct {
+	// maximum number of ids a single BatchGetUsers call may resolve
+	BatchGetMaxIDs int `yaml:"batchGetMaxIds"`
+	// number of lookups BatchGetUsers runs concurrently
+	BatchGetConcurrency int `yaml:"batchGetConcurrency"`
+	// seconds a list page token stays valid
+	PageTokenTTL int `yaml:"pageTokenTTL"`
+	// largest page a list call may ask for
+	MaxPageSize int `yaml:"maxPageSize"`
+	// seconds between WatchUsers polls of the change log
+	WatchPollInterval int `yaml:"watchPollInterval"`
+}
+
+type AvatarConfig struct {
+	// directory backing the local avatar blob store
+	Dir string `yaml:"dir"`
+	// URL prefix avatar blob keys are appended to, e.g. http://localhost:3002
+	PublicBaseURL string `yaml:"publicBaseURL"`
+	// largest accepted upload in bytes
+	MaxBytes int64 `yaml:"maxBytes"`
+	// edge lengths in pixels of the square thumbnails rendered per upload
+	Sizes []int `yaml:"sizes"`
+}
+
+type PresenceConfig struct {
+	// seconds without a heartbeat before a user is considered offline
+	HeartbeatTTL int `yaml:"heartbeatTTL"`
+}
+
+// CacheConfig sizes a two-tier cache; TTLs are in seconds.
+type CacheConfig struct {
+	// entries held in each instance's in-process tier
+	Size     int `yaml:"size"`
+	LocalTTL int `yaml:"localTTL"`
+	RedisTTL int `yaml:"redisTTL"`
+	// how long a lookup that found nothing is cached
+	NegativeTTL int `yaml:"negativeTTL"`
+}
+
+type ConversationConfig struct {
+	// largest number of members a group may have, owner included; best-effort,
+	// as concurrent adds are not serialised
+	MaxGroupMembers int `yaml:"maxGroupMembers"`
+	// longest group name in characters
+	MaxNameLength int `yaml:"maxNameLength"`
+	// seconds a list page token stays valid
+	PageTokenTTL int `yaml:"pageTokenTTL"`
+	// largest page a list call may ask for
+	MaxPageSize int `yaml:"maxPageSize"`
+}
+
+type MessageConfig struct {
+	// longest message body in characters
+	MaxBodyLength int `yaml:"maxBodyLength"`
+	// messages returned when a list call sets no page limit
+	DefaultPageSize int `yaml:"defaultPageSize"`
+	// largest page a list call may ask for
+	MaxPageSize int `yaml:"maxPageSize"`
+	// seconds after sending during which the author may edit a message
+	EditWindow int `yaml:"editWindow"`
+}
+
+// UnreadConfig tunes the unread counters kept in Redis.
+type UnreadConfig struct {
+	// counts stop at this many; clients show it as "99+" or similar
+	MaxCount int `yaml:"maxCount"`
+	// seconds between reconciliation passes against Cassandra
+	ReconcileInterval int `yaml:"reconcileInterval"`
+	// counters recomputed per pass
+	ReconcileBatch int `yaml:"reconcileBatch"`
+}
+
+type ReactionsConfig struct {
+	// names of the custom emoji members may react with as :name:, on top of
+	// the Unicode ones
+	CustomEmoji []string `yaml:"customEmoji"`
+	// distinct emoji one member may react to a single message with
+	MaxPerUser int `yaml:"maxPerUser"`
+}
+
+// EventsConfig sizes the per-user realtime event logs in Redis.
+type EventsConfig struct {
+	// events kept per user for resuming, approximately
+	MaxLen int64 `yaml:"maxLen"`
+	// seconds a user's log outlives its last event
+	Retention int `yaml:"retention"`
+}
+
+type RealtimeConfig struct {
+	// frames queued per connection before it counts as too slow
+	SendBuffer int `yaml:"sendBuffer"`
+	// seconds between heartbeats on an idle connection
+	HeartbeatInterval int `yaml:"heartbeatInterval"`
+	// conversations one connection may subscribe to
+	MaxSubscriptions int `yaml:"maxSubscriptions"`
+}
+
+// TypingConfig bounds typing indicators, which live only in Redis.
+type TypingConfig struct {
+	// seconds a typing indicator lasts unless refreshed
+	TTL int `yaml:"ttl"`
+	// StartTyping calls one user may make per window
+	RateLimit int `yaml:"rateLimit"`
+	// seconds in a rate limit window
+	RateWindow int `yaml:"rateWindow"`
+}
+
+// SettingsConfig holds the defaults a user sees until they change a setting.
+type SettingsConfig struct {
+	// one of system, light or dark
+	Theme  string `yaml:"theme"`
+	Locale string `yaml:"locale"`
+
+	PushNotifications  bool `yaml:"pushNotifications"`
+	EmailNotifications bool `yaml:"emailNotifications"`
+	NotificationSound  bool `yaml:"notificationSound"`
+	ShowPreviews       bool `yaml:"showPreviews"`
+
+	HideEmail    bool `yaml:"hideEmail"`
+	HideLastSeen bool `yaml:"hideLastSeen"`
+
+	// maximum number of client-defined preferences per user
+	MaxPreferences int `yaml:"maxPreferences"`
+}
+
 // LoadConfig loads a YAML config file into the receiver.
 func (c *Config) LoadConfig(path string) error {
 	// read the file by the path
@@ -0,0 +1,244 @@
+// Package events keeps each user's realtime events in a Redis stream, so a
+// client that reconnects can pick up where it left off.
+//
+// Every event appended for a user gets the next number of that user's
+// sequence, stored as the stream entry id 0-<sequence>. Streams are trimmed
+// to about MaxLen entries and expire Retention after the last append, while
+// the sequence itself is kept so it never restarts. Ephemeral events such as
+// typing skip the stream and go out over a pubsub.Broker to whoever is
+// listening, on any instance.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/pubsub"
+	"github.com/yaninyzwitty/chat/packages/shared/streams"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultMaxLen    = 1000
+	DefaultRetention = 24 * time.Hour
+
+	// ephemeral events are only for listeners already waiting, so the
+	// default broker keeps few of them and not for long
+	liveMaxLen    = 100
+	liveRetention = time.Minute
+)
+
+// appendScript takes the next sequence and adds the event under it in one
+// step, so concurrent appends cannot write their ids out of order.
+var appendScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[2])
+redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[2], '0-' .. seq, 'event', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return seq
+`)
+
+type Config struct {
+	// entries kept per user, approximately
+	MaxLen int64
+	// how long a stream outlives its last append
+	Retention time.Duration
+	// Live carries ephemeral events; a Redis broker on the log's client when
+	// nil
+	Live pubsub.Broker
+}
+
+type Log struct {
+	rdb *redis.Client
+	cfg Config
+	// every Follow on this log shares one blocking read
+	reader *streams.Reader
+}
+
+// FromConfig converts the events section of the service config.
+func FromConfig(cfg config.EventsConfig) Config {
+	return Config{
+		MaxLen:    cfg.MaxLen,
+		Retention: time.Duration(cfg.Retention) * time.Second,
+	}
+}
+
+// NewLog returns a Log on rdb, using the defaults for unset config.
+func NewLog(rdb *redis.Client, cfg Config) *Log {
+	if cfg.MaxLen <= 0 {
+		cfg.MaxLen = DefaultMaxLen
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.Live == nil {
+		cfg.Live = pubsub.NewRedisBroker(rdb, pubsub.RedisConfig{MaxLen: liveMaxLen, Retention: liveRetention})
+	}
+	return &Log{rdb: rdb, cfg: cfg, reader: streams.NewReader(rdb)}
+}
+
+// the user id is hash tagged so a user's keys share a cluster slot
+func streamKey(userID string) string   { return "events:{" + userID + "}:log" }
+func sequenceKey(userID string) string { return "events:{" + userID + "}:seq" }
+
+// Append adds ev to the log of each of userIDs. The event's own sequence is
+// ignored; each recipient's log numbers it.
+func (l *Log) Append(ctx context.Context, ev *realtimev1.Event, userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	data, err := marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	pipe := l.rdb.Pipeline()
+	for _, id := range userIDs {
+		appendScript.Eval(ctx, pipe, []string{streamKey(id), sequenceKey(id)},
+			data, l.cfg.MaxLen, l.cfg.Retention.Milliseconds())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+	return nil
+}
+
+// Publish sends ev to whichever of userIDs are listening, without storing it.
+func (l *Log) Publish(ctx context.Context, ev *realtimev1.Event, userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	data, err := marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := l.cfg.Live.Publish(ctx, data, pubsub.UserTopics(userIDs...)...); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Live returns the broker ephemeral events go out on, for other ephemeral
+// traffic to share.
+func (l *Log) Live() pubsub.Broker { return l.cfg.Live }
+
+// Head returns the sequence of the last event appended for userID, or 0.
+func (l *Log) Head(ctx context.Context, userID string) (uint64, error) {
+	seq, err := l.rdb.Get(ctx, sequenceKey(userID)).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sequence: %w", err)
+	}
+	return seq, nil
+}
+
+// Resume decides where a client that last saw sequence last continues from.
+// resumed is false when events after last are no longer all kept, or last is
+// unknown; the client then starts over at the returned head. A last of 0
+// starts at the head.
+func (l *Log) Resume(ctx context.Context, userID string, last uint64) (from uint64, resumed bool, err error) {
+	head, err := l.Head(ctx, userID)
+	if err != nil {
+		return 0, false, err
+	}
+	if last == 0 || last > head {
+		return head, false, nil
+	}
+	if last == head {
+		return head, true, nil
+	}
+
+	oldest, err := l.rdb.XRangeN(ctx, streamKey(userID), "-", "+", 1).Result()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read event log: %w", err)
+	}
+	if len(oldest) == 0 {
+		return head, false, nil
+	}
+	first, err := parseSequence(oldest[0].ID)
+	if err != nil {
+		return 0, false, err
+	}
+	if first > last+1 {
+		return head, false, nil
+	}
+	return last, true, nil
+}
+
+// Follow calls fn with each event of userID after sequence after, in order,
+// until ctx is done or the log cannot be read or decoded. All of a Log's
+// followers wait on one read, so fn must not block.
+func (l *Log) Follow(ctx context.Context, userID string, after uint64, fn func(*realtimev1.Event)) error {
+	err := l.reader.Follow(ctx, streamKey(userID), "0-"+strconv.FormatUint(after, 10), func(msg redis.XMessage) error {
+		ev, err := decode(msg)
+		if err != nil {
+			return err
+		}
+		fn(ev)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read event log: %w", err)
+	}
+	return err
+}
+
+// Listen calls fn with each event published for userID until ctx is done.
+// Events published before Listen has subscribed are missed, and events that
+// cannot be decoded are skipped. As with Follow, fn must not block.
+func (l *Log) Listen(ctx context.Context, userID string, fn func(*realtimev1.Event)) error {
+	// each listener subscribes on its own, so every one sees every event
+	err := l.cfg.Live.Subscribe(ctx, pubsub.UserTopic(userID), "", "", func(_ context.Context, msg *pubsub.Message) error {
+		ev := &realtimev1.Event{}
+		if err := proto.Unmarshal(msg.Data, ev); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		fn(ev)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	return err
+}
+
+func marshal(ev *realtimev1.Event) ([]byte, error) {
+	ev = proto.CloneOf(ev)
+	ev.Sequence = 0
+	data, err := proto.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return data, nil
+}
+
+func decode(msg redis.XMessage) (*realtimev1.Event, error) {
+	seq, err := parseSequence(msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := msg.Values["event"].(string)
+
+	ev := &realtimev1.Event{}
+	if err := proto.Unmarshal([]byte(data), ev); err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", msg.ID, err)
+	}
+	ev.Sequence = seq
+	return ev, nil
+}
+
+func parseSequence(id string) (uint64, error) {
+	seq, err := strconv.ParseUint(strings.TrimPrefix(id, "0-"), 10, 64)
+	if err != nil || !strings.HasPrefix(id, "0-") {
+		return 0, fmt.Errorf("unexpected event id %q", id)
+	}
+	return seq, nil
+}
@@ -0,0 +1,156 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+)
+
+func newLog(t *testing.T, cfg events.Config) (*events.Log, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return events.NewLog(rdb, cfg), mr
+}
+
+func typing(conv string) *realtimev1.Event {
+	return &realtimev1.Event{
+		ConversationId: conv,
+		Payload:        &realtimev1.Event_Typing{Typing: &realtimev1.TypingEvent{UserId: "u", Typing: true}},
+	}
+}
+
+// follow collects the first n events of userID after sequence after.
+func follow(t *testing.T, log *events.Log, userID string, after uint64, n int) []*realtimev1.Event {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var evs []*realtimev1.Event
+	err := log.Follow(ctx, userID, after, func(ev *realtimev1.Event) {
+		evs = append(evs, ev)
+		if len(evs) == n {
+			cancel()
+		}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	return evs
+}
+
+func TestAppendNumbersPerUser(t *testing.T) {
+	ctx := context.Background()
+	log, _ := newLog(t, events.Config{})
+
+	require.NoError(t, log.Append(ctx, typing("c1"), "alice", "bob"))
+	require.NoError(t, log.Append(ctx, typing("c2"), "alice"))
+
+	evs := follow(t, log, "alice", 0, 2)
+	require.Len(t, evs, 2)
+	require.Equal(t, uint64(1), evs[0].Sequence)
+	require.Equal(t, "c2", evs[1].ConversationId)
+	require.Equal(t, uint64(2), evs[1].Sequence)
+
+	evs = follow(t, log, "alice", 1, 1)
+	require.Len(t, evs, 1)
+	require.Equal(t, uint64(2), evs[0].Sequence)
+
+	head, err := log.Head(ctx, "bob")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), head)
+}
+
+func TestFollowSeesNewEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log, _ := newLog(t, events.Config{})
+
+	got := make(chan *realtimev1.Event, 4)
+	done := make(chan error, 1)
+	go func() { done <- log.Follow(ctx, "alice", 0, func(ev *realtimev1.Event) { got <- ev }) }()
+	go func() { _ = log.Follow(ctx, "bob", 0, func(*realtimev1.Event) {}) }()
+
+	require.NoError(t, log.Append(ctx, typing("c"), "alice", "bob"))
+	select {
+	case ev := <-got:
+		require.Equal(t, uint64(1), ev.Sequence)
+	case <-time.After(3 * time.Second):
+		t.Fatal("appended event not followed")
+	}
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestResume(t *testing.T) {
+	ctx := context.Background()
+	log, mr := newLog(t, events.Config{})
+
+	for range 3 {
+		require.NoError(t, log.Append(ctx, typing("c"), "alice"))
+	}
+
+	from, resumed, err := log.Resume(ctx, "alice", 1)
+	require.NoError(t, err)
+	require.True(t, resumed)
+	require.Equal(t, uint64(1), from)
+
+	// a fresh connection starts at the head
+	from, resumed, err = log.Resume(ctx, "alice", 0)
+	require.NoError(t, err)
+	require.False(t, resumed)
+	require.Equal(t, uint64(3), from)
+
+	// a sequence the log never reached
+	_, resumed, err = log.Resume(ctx, "alice", 9)
+	require.NoError(t, err)
+	require.False(t, resumed)
+
+	// once the stream is trimmed, older positions are gone
+	mr.Del("events:{alice}:log")
+	require.NoError(t, log.Append(ctx, typing("c"), "alice"))
+	from, resumed, err = log.Resume(ctx, "alice", 1)
+	require.NoError(t, err)
+	require.False(t, resumed)
+	require.Equal(t, uint64(4), from)
+
+	from, resumed, err = log.Resume(ctx, "alice", 3)
+	require.NoError(t, err)
+	require.True(t, resumed)
+	require.Equal(t, uint64(3), from)
+}
+
+func TestPublishListen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log, _ := newLog(t, events.Config{})
+
+	got := make(chan *realtimev1.Event, 1)
+	done := make(chan error, 1)
+	go func() { done <- log.Listen(ctx, "alice", func(ev *realtimev1.Event) { got <- ev }) }()
+	// let the listener subscribe
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, log.Publish(ctx, typing("c"), "alice"))
+
+	select {
+	case ev := <-got:
+		require.Equal(t, "c", ev.ConversationId)
+		require.Zero(t, ev.Sequence)
+	case <-time.After(time.Second):
+		t.Fatal("published event not received")
+	}
+
+	// ephemeral events never reach the log
+	head, err := log.Head(ctx, "alice")
+	require.NoError(t, err)
+	require.Zero(t, head)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
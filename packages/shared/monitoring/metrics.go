@@ -14,6 +14,9 @@ type Metrics struct {
 	Stage    prometheus.Gauge
 	Duration *prometheus.HistogramVec
 	Errors   *prometheus.CounterVec
+	// cache lookups answered, or not, by each tier
+	CacheHits   *prometheus.CounterVec
+	CacheMisses *prometheus.CounterVec
 }
 
 // NewMetrics registers and returns a Metrics instance.
@@ -35,10 +38,20 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name:      "errors_total",
 			Help:      "Count of errors by operation and backend",
 		}, []string{"op", "db"}),
+		CacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "myapp",
+			Name:      "cache_hits_total",
+			Help:      "Count of cache hits by cache and tier",
+		}, []string{"cache", "tier"}),
+		CacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "myapp",
+			Name:      "cache_misses_total",
+			Help:      "Count of cache misses by cache and tier",
+		}, []string{"cache", "tier"}),
 	}
 
 	// register metrics with prometheus
-	reg.MustRegister(m.Stage, m.Duration, m.Errors)
+	reg.MustRegister(m.Stage, m.Duration, m.Errors, m.CacheHits, m.CacheMisses)
 	return m
 }
 
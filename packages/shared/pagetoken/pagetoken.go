@@ -0,0 +1,139 @@
+// Package pagetoken turns driver paging state into opaque page tokens that
+// clients can hand back but not forge, reuse elsewhere or keep forever.
+//
+// A token is the base64url encoding of
+//
+//	version (1) | expiry unix seconds (8) | page size (4) | page state | HMAC-SHA256 (32)
+//
+// where the MAC also covers a caller-chosen scope, binding the token to the
+// list call and caller it was issued for.
+package pagetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	version    = 1
+	headerSize = 1 + 8 + 4
+	macSize    = sha256.Size
+
+	// MinSecretLength is the shortest HMAC secret New accepts.
+	MinSecretLength = 32
+	// DefaultTTL is how long tokens stay valid when New is given no TTL.
+	DefaultTTL = time.Hour
+)
+
+var encoding = base64.RawURLEncoding
+
+// Page is the decoded content of a page token.
+type Page struct {
+	// State is the driver paging state to resume from.
+	State []byte
+	// Size is the page size the token was issued with.
+	Size int32
+	// ExpiresAt is when the token stops being accepted.
+	ExpiresAt time.Time
+}
+
+// Limit returns the page size to query with: requested, else the size the
+// token was issued with, and never more than limit.
+func (p Page) Limit(requested uint32, limit int32) int32 {
+	size := int32(min(requested, math.MaxInt32))
+	if size == 0 {
+		size = p.Size
+	}
+	return min(size, limit)
+}
+
+// Codec issues and validates page tokens with a shared secret.
+type Codec struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// New returns a Codec signing with secret whose tokens live for ttl, or
+// DefaultTTL when ttl is not positive.
+func New(secret []byte, ttl time.Duration) (*Codec, error) {
+	if len(secret) < MinSecretLength {
+		return nil, errors.New("page token secret must be at least 32 bytes")
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Codec{secret: secret, ttl: ttl, now: time.Now}, nil
+}
+
+// Encode returns a token resuming at state with pages of size. An empty state
+// means there are no further pages and encodes to the empty string.
+func (c *Codec) Encode(scope string, state []byte, size int32) string {
+	if len(state) == 0 {
+		return ""
+	}
+
+	buf := make([]byte, headerSize, headerSize+len(state)+macSize)
+	buf[0] = version
+	binary.BigEndian.PutUint64(buf[1:9], uint64(c.now().Add(c.ttl).Unix()))
+	binary.BigEndian.PutUint32(buf[9:13], uint32(size))
+	buf = append(buf, state...)
+	buf = append(buf, c.mac(scope, buf)...)
+
+	return encoding.EncodeToString(buf)
+}
+
+// Decode validates token against scope. The empty token decodes to the zero
+// Page, meaning the first page. Any malformed, tampered, foreign or expired
+// token is rejected with codes.InvalidArgument.
+func (c *Codec) Decode(scope, token string) (Page, error) {
+	if token == "" {
+		return Page{}, nil
+	}
+
+	buf, err := encoding.DecodeString(token)
+	if err != nil || len(buf) < headerSize+macSize {
+		return Page{}, invalid("malformed page token")
+	}
+	if buf[0] != version {
+		return Page{}, invalid("unsupported page token version")
+	}
+
+	payload, sum := buf[:len(buf)-macSize], buf[len(buf)-macSize:]
+	if !hmac.Equal(sum, c.mac(scope, payload)) {
+		return Page{}, invalid("invalid page token")
+	}
+
+	page := Page{
+		ExpiresAt: time.Unix(int64(binary.BigEndian.Uint64(payload[1:9])), 0),
+		Size:      int32(binary.BigEndian.Uint32(payload[9:13])),
+		State:     payload[headerSize:],
+	}
+	if !c.now().Before(page.ExpiresAt) {
+		return Page{}, invalid("page token has expired")
+	}
+	return page, nil
+}
+
+func (c *Codec) mac(scope string, payload []byte) []byte {
+	m := hmac.New(sha256.New, c.secret)
+	// length-prefix the scope so scope and payload cannot be re-split
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(scope)))
+	m.Write(n[:])
+	m.Write([]byte(scope))
+	m.Write(payload)
+	return m.Sum(nil)
+}
+
+func invalid(msg string) error {
+	return status.Error(codes.InvalidArgument, msg)
+}
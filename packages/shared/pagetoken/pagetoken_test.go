@@ -0,0 +1,86 @@
+package pagetoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var secret = []byte(strings.Repeat("s", MinSecretLength))
+
+func TestRoundTrip(t *testing.T) {
+	c, err := New(secret, time.Minute)
+	require.NoError(t, err)
+
+	token := c.Encode("list_users:alice", []byte{0x00, 0xff, 0x10}, 25)
+	require.NotContains(t, token, "=")
+
+	page, err := c.Decode("list_users:alice", token)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0xff, 0x10}, page.State)
+	require.Equal(t, int32(25), page.Size)
+
+	// no state means no next page, and the empty token means the first page
+	require.Empty(t, c.Encode("list_users:alice", nil, 25))
+	page, err = c.Decode("list_users:alice", "")
+	require.NoError(t, err)
+	require.Nil(t, page.State)
+}
+
+func TestDecodeRejects(t *testing.T) {
+	c, err := New(secret, time.Minute)
+	require.NoError(t, err)
+	token := c.Encode("scope", []byte("state"), 10)
+
+	raw, err := encoding.DecodeString(token)
+	require.NoError(t, err)
+	tampered := append([]byte(nil), raw...)
+	tampered[headerSize] ^= 1
+	future := append([]byte(nil), raw...)
+	future[0] = version + 1
+
+	other, err := New([]byte(strings.Repeat("o", MinSecretLength)), time.Minute)
+	require.NoError(t, err)
+
+	expired, err := New(secret, time.Minute)
+	require.NoError(t, err)
+	expired.now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+
+	testCases := []struct {
+		name  string
+		scope string
+		token string
+	}{
+		{name: "error:not_base64", scope: "scope", token: "not base64!"},
+		{name: "error:truncated", scope: "scope", token: token[:10]},
+		{name: "error:tampered", scope: "scope", token: encoding.EncodeToString(tampered)},
+		{name: "error:unknown_version", scope: "scope", token: encoding.EncodeToString(future)},
+		{name: "error:other_scope", scope: "other", token: token},
+		{name: "error:other_secret", scope: "scope", token: other.Encode("scope", []byte("state"), 10)},
+		{name: "error:expired", scope: "scope", token: expired.Encode("scope", []byte("state"), 10)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := c.Decode(tc.scope, tc.token)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	page := Page{Size: 20}
+	require.Equal(t, int32(50), page.Limit(50, 200))
+	require.Equal(t, int32(20), page.Limit(0, 200))
+	require.Equal(t, int32(200), page.Limit(1000, 200))
+	require.Equal(t, int32(200), Page{Size: 500}.Limit(0, 200))
+}
+
+func TestNewRejectsShortSecret(t *testing.T) {
+	_, err := New([]byte("short"), time.Minute)
+	require.Error(t, err)
+}
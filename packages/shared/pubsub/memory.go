@@ -0,0 +1,114 @@
+package pubsub
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// MemoryBroker is a Broker within one process, for tests and single
+// instance setups. Messages are kept until the broker is dropped.
+type MemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+}
+
+type memoryTopic struct {
+	msgs   []*Message
+	groups map[string]*memoryGroup
+	// closed and replaced on every publish, waking blocked subscribers
+	notify chan struct{}
+}
+
+type memoryGroup struct {
+	// index of the next message to hand out
+	next int
+	// messages whose handler failed, for the next consumer to subscribe
+	pending []*Message
+}
+
+var _ Broker = (*MemoryBroker)(nil)
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{topics: map[string]*memoryTopic{}}
+}
+
+// topic returns the named topic, creating it if needed. Callers hold mu.
+func (b *MemoryBroker) topic(name string) *memoryTopic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{groups: map[string]*memoryGroup{}, notify: make(chan struct{})}
+		b.topics[name] = t
+	}
+	return t
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, data []byte, topics ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, name := range topics {
+		t := b.topic(name)
+		t.msgs = append(t.msgs, &Message{
+			ID:    strconv.Itoa(len(t.msgs)+1) + "-0",
+			Topic: name,
+			Data:  append([]byte(nil), data...),
+		})
+		close(t.notify)
+		t.notify = make(chan struct{})
+	}
+	return nil
+}
+
+// Subscribe behaves like the Redis broker's, except that a message whose
+// handler fails waits for the next consumer to subscribe to the group rather
+// than for a timeout.
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic, group, consumer string, fn Handler) error {
+	b.mu.Lock()
+	t := b.topic(topic)
+	g, ok := t.groups[group]
+	if !ok {
+		g = &memoryGroup{next: len(t.msgs)}
+		// a subscription without a group is not kept
+		if group != "" {
+			t.groups[group] = g
+		}
+	}
+	retry := g.pending
+	g.pending = nil
+	b.mu.Unlock()
+
+	deliver := func(msg *Message) {
+		if err := fn(ctx, msg); err != nil && group != "" {
+			b.mu.Lock()
+			g.pending = append(g.pending, msg)
+			b.mu.Unlock()
+		}
+	}
+	for _, msg := range retry {
+		deliver(msg)
+	}
+
+	for {
+		b.mu.Lock()
+		var msg *Message
+		if g.next < len(t.msgs) {
+			msg = t.msgs[g.next]
+			g.next++
+		}
+		notify := t.notify
+		b.mu.Unlock()
+
+		if msg != nil {
+			deliver(msg)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package pubsub carries messages between service instances, so an event
+// produced on one replica reaches the connections held by every other.
+//
+// Messages are published to topics, one per user or conversation, and read
+// through consumer groups: every group sees every message on a topic, while
+// the consumers inside a group share them. An instance that must see
+// everything subscribes under its own group; workers that split the load
+// subscribe under a common one. Listeners that come and go, such as a
+// connection waiting for its user's events, subscribe without a group.
+package pubsub
+
+import "context"
+
+type Message struct {
+	// ID orders messages within a topic
+	ID    string
+	Topic string
+	Data  []byte
+}
+
+// Handler processes one message. A message whose handler fails is not
+// acknowledged and is delivered again later, possibly to another consumer of
+// the group.
+type Handler func(ctx context.Context, msg *Message) error
+
+type Broker interface {
+	// Publish adds data to each of topics.
+	Publish(ctx context.Context, data []byte, topics ...string) error
+	// Subscribe passes the messages of topic to fn, one at a time, until ctx
+	// is done. A group created by the call starts with the messages
+	// published after it; an existing group resumes where it stopped. With
+	// an empty group the subscription is the caller's alone: it gets the
+	// messages published while it runs, consumer is ignored, failed messages
+	// are not retried and nothing is kept for it once it stops. Such
+	// subscriptions may share a connection, so their fn must not block.
+	Subscribe(ctx context.Context, topic, group, consumer string, fn Handler) error
+}
+
+// UserTopic is the topic for events addressed to one user.
+func UserTopic(userID string) string { return "user:" + userID }
+
+// ConversationTopic is the topic for events in one conversation.
+func ConversationTopic(conversationID string) string { return "conversation:" + conversationID }
+
+// UserTopics returns the topics of each of userIDs.
+func UserTopics(userIDs ...string) []string {
+	topics := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		topics[i] = UserTopic(id)
+	}
+	return topics
+}
@@ -0,0 +1,160 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/yaninyzwitty/chat/packages/shared/pubsub"
+)
+
+// brokers runs test against each implementation.
+func brokers(t *testing.T, test func(t *testing.T, b pubsub.Broker)) {
+	t.Run("memory", func(t *testing.T) { test(t, pubsub.NewMemoryBroker()) })
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { _ = rdb.Close() })
+		test(t, pubsub.NewRedisBroker(rdb, pubsub.RedisConfig{}))
+	})
+}
+
+// collector records the messages a subscriber receives.
+type collector struct {
+	mu   sync.Mutex
+	got  []string
+	fail bool
+}
+
+func (c *collector) handle(_ context.Context, msg *pubsub.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fail {
+		return errors.New("failed")
+	}
+	c.got = append(c.got, string(msg.Data))
+	return nil
+}
+
+func (c *collector) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.got...)
+}
+
+// subscribe starts a subscriber and waits until its group exists, returning
+// a function that stops it.
+func subscribe(t *testing.T, b pubsub.Broker, topic, group, consumer string, fn pubsub.Handler) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		done <- b.Subscribe(ctx, topic, group, consumer, fn)
+	}()
+	<-started
+	// a group is created on subscribe; give it a moment to exist
+	time.Sleep(20 * time.Millisecond)
+	stop := func() {
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	}
+	t.Cleanup(cancel)
+	return stop
+}
+
+func TestEveryGroupSeesEveryMessage(t *testing.T) {
+	brokers(t, func(t *testing.T, b pubsub.Broker) {
+		ctx := context.Background()
+		topic := pubsub.ConversationTopic("c1")
+
+		var a, c collector
+		stopA := subscribe(t, b, topic, "instance-a", "a", a.handle)
+		stopC := subscribe(t, b, topic, "instance-b", "b", c.handle)
+
+		require.NoError(t, b.Publish(ctx, []byte("one"), topic))
+		require.NoError(t, b.Publish(ctx, []byte("two"), topic, pubsub.UserTopic("u1")))
+
+		want := []string{"one", "two"}
+		require.Eventually(t, func() bool {
+			return len(a.received()) == 2 && len(c.received()) == 2
+		}, 3*time.Second, 5*time.Millisecond)
+		require.Equal(t, want, a.received())
+		require.Equal(t, want, c.received())
+		stopA()
+		stopC()
+	})
+}
+
+func TestGroupSharesMessages(t *testing.T) {
+	brokers(t, func(t *testing.T, b pubsub.Broker) {
+		ctx := context.Background()
+		topic := pubsub.UserTopic("u1")
+
+		var w1, w2 collector
+		stop1 := subscribe(t, b, topic, "workers", "w1", w1.handle)
+		stop2 := subscribe(t, b, topic, "workers", "w2", w2.handle)
+
+		for _, m := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, b.Publish(ctx, []byte(m), topic))
+		}
+
+		require.Eventually(t, func() bool {
+			return len(w1.received())+len(w2.received()) == 4
+		}, 3*time.Second, 5*time.Millisecond)
+		require.ElementsMatch(t, []string{"a", "b", "c", "d"}, append(w1.received(), w2.received()...))
+		stop1()
+		stop2()
+	})
+}
+
+func TestSubscribingWithoutGroup(t *testing.T) {
+	brokers(t, func(t *testing.T, b pubsub.Broker) {
+		ctx := context.Background()
+		topic := pubsub.UserTopic("u1")
+		require.NoError(t, b.Publish(ctx, []byte("before"), topic))
+
+		var a, c collector
+		stopA := subscribe(t, b, topic, "", "", a.handle)
+		stopC := subscribe(t, b, topic, "", "", c.handle)
+		require.NoError(t, b.Publish(ctx, []byte("one"), topic))
+		require.NoError(t, b.Publish(ctx, []byte("two"), topic))
+
+		want := []string{"one", "two"}
+		require.Eventually(t, func() bool {
+			return len(a.received()) == 2 && len(c.received()) == 2
+		}, 3*time.Second, 5*time.Millisecond)
+		require.Equal(t, want, a.received())
+		require.Equal(t, want, c.received())
+		stopA()
+		stopC()
+	})
+}
+
+func TestFailedMessagesAreRedelivered(t *testing.T) {
+	brokers(t, func(t *testing.T, b pubsub.Broker) {
+		ctx := context.Background()
+		topic := pubsub.UserTopic("u1")
+
+		failing := &collector{fail: true}
+		stop := subscribe(t, b, topic, "g", "c1", failing.handle)
+		require.NoError(t, b.Publish(ctx, []byte("retry me"), topic))
+		// let the handler fail before stopping
+		time.Sleep(100 * time.Millisecond)
+		stop()
+
+		var ok collector
+		stop = subscribe(t, b, topic, "g", "c1", ok.handle)
+		require.Eventually(t, func() bool {
+			return len(ok.received()) == 1
+		}, 3*time.Second, 5*time.Millisecond)
+		require.Equal(t, []string{"retry me"}, ok.received())
+		stop()
+	})
+}
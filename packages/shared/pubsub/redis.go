@@ -0,0 +1,205 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yaninyzwitty/chat/packages/shared/streams"
+)
+
+const (
+	DefaultMaxLen    = 10000
+	DefaultRetention = 24 * time.Hour
+	DefaultClaimIdle = 30 * time.Second
+
+	// readBlock bounds each wait for new messages, and so how long a
+	// subscriber takes to notice its context is done
+	readBlock = time.Second
+	readBatch = 100
+)
+
+type RedisConfig struct {
+	// entries kept per topic, approximately
+	MaxLen int64
+	// how long a topic outlives its last message
+	Retention time.Duration
+	// how long a message may sit unacknowledged with one consumer before
+	// another consumer of the group takes it over
+	ClaimIdle time.Duration
+}
+
+// RedisBroker keeps each topic in a Redis stream and maps groups onto the
+// stream's consumer groups.
+type RedisBroker struct {
+	rdb *redis.Client
+	cfg RedisConfig
+	// subscriptions without a group share one blocking read
+	reader *streams.Reader
+}
+
+var _ Broker = (*RedisBroker)(nil)
+
+// NewRedisBroker returns a broker on rdb, using the defaults for unset
+// config.
+func NewRedisBroker(rdb *redis.Client, cfg RedisConfig) *RedisBroker {
+	if cfg.MaxLen <= 0 {
+		cfg.MaxLen = DefaultMaxLen
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = DefaultClaimIdle
+	}
+	return &RedisBroker{rdb: rdb, cfg: cfg, reader: streams.NewReader(rdb)}
+}
+
+// the topic is hash tagged so its keys share a cluster slot
+func topicKey(topic string) string { return "pubsub:{" + topic + "}" }
+
+func (b *RedisBroker) Publish(ctx context.Context, data []byte, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	_, err := b.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, topic := range topics {
+			key := topicKey(topic)
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: key,
+				MaxLen: b.cfg.MaxLen,
+				Approx: true,
+				Values: []any{"data", data},
+			})
+			pipe.PExpire(ctx, key, b.cfg.Retention)
+		}
+		return nil
+	})
+	return err
+}
+
+// Subscribe first retries what this consumer left unacknowledged, then reads
+// new messages, and now and then takes over messages other consumers of the
+// group have held longer than ClaimIdle.
+func (b *RedisBroker) Subscribe(ctx context.Context, topic, group, consumer string, fn Handler) error {
+	if group == "" {
+		return b.follow(ctx, topic, fn)
+	}
+
+	key := topicKey(topic)
+	err := b.rdb.XGroupCreateMkStream(ctx, key, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	// messages delivered to this consumer before it last stopped
+	for {
+		streams, err := b.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{key, "0"},
+			Count:    readBatch,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			break
+		}
+		// failed messages stay pending, so stop rather than loop on them
+		if b.handle(ctx, topic, group, streams[0].Messages, fn) == 0 {
+			break
+		}
+	}
+
+	nextClaim := time.Now().Add(b.cfg.ClaimIdle)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if time.Now().After(nextClaim) {
+			msgs, _, err := b.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   key,
+				Group:    group,
+				Consumer: consumer,
+				MinIdle:  b.cfg.ClaimIdle,
+				Start:    "0",
+				Count:    readBatch,
+			}).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return err
+			}
+			b.handle(ctx, topic, group, msgs, fn)
+			nextClaim = time.Now().Add(b.cfg.ClaimIdle)
+		}
+
+		streams, err := b.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{key, ">"},
+			Count:    readBatch,
+			Block:    readBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		for _, s := range streams {
+			b.handle(ctx, topic, group, s.Messages, fn)
+		}
+	}
+}
+
+// follow reads topic without a group, from its newest message on, on the
+// broker's shared reader. The newest id is looked up once rather than read as
+// $, so nothing published before the reader picks the topic up is skipped.
+// fn runs on the shared reader too, so it must not block.
+func (b *RedisBroker) follow(ctx context.Context, topic string, fn Handler) error {
+	key := topicKey(topic)
+	last := "0-0"
+	newest, err := b.rdb.XRevRangeN(ctx, key, "+", "-", 1).Result()
+	if err != nil {
+		return err
+	}
+	if len(newest) > 0 {
+		last = newest[0].ID
+	}
+
+	return b.reader.Follow(ctx, key, last, func(m redis.XMessage) error {
+		data, _ := m.Values["data"].(string)
+		if err := fn(ctx, &Message{ID: m.ID, Topic: topic, Data: []byte(data)}); err != nil {
+			slog.Warn("pubsub handler failed", "topic", topic, "id", m.ID, "error", err)
+		}
+		return nil
+	})
+}
+
+// handle runs fn over msgs, acknowledging those it accepts, and returns how
+// many it accepted.
+func (b *RedisBroker) handle(ctx context.Context, topic, group string, msgs []redis.XMessage, fn Handler) int {
+	var acked []string
+	for _, m := range msgs {
+		data, _ := m.Values["data"].(string)
+		if err := fn(ctx, &Message{ID: m.ID, Topic: topic, Data: []byte(data)}); err != nil {
+			slog.Warn("pubsub handler failed", "topic", topic, "group", group, "id", m.ID, "error", err)
+			continue
+		}
+		acked = append(acked, m.ID)
+	}
+	if len(acked) > 0 {
+		if err := b.rdb.XAck(ctx, topicKey(topic), group, acked...).Err(); err != nil {
+			// unacknowledged messages are delivered again, which handlers tolerate
+			slog.Warn("failed to acknowledge messages", "topic", topic, "group", group, "error", err)
+		}
+	}
+	return len(acked)
+}
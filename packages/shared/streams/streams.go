@@ -0,0 +1,195 @@
+// Package streams follows many Redis streams over a single connection.
+//
+// A blocking XREAD holds its connection for as long as it waits, so one per
+// listener would drain the client's pool. A Reader instead waits on every
+// followed stream in one read and hands each entry to whoever follows it.
+package streams
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// readBlock bounds each wait, and so how long a stream added meanwhile
+	// waits to be read
+	readBlock = time.Second
+	readBatch = 100
+)
+
+// Reader follows streams for any number of callers with at most one read in
+// flight. The read runs while anyone follows and stops once nobody does.
+type Reader struct {
+	rdb *redis.Client
+
+	mu        sync.Mutex
+	followers map[string]map[*follower]struct{}
+	running   bool
+}
+
+type follower struct {
+	// id of the last entry passed on
+	last string
+	fn   func(redis.XMessage) error
+	done chan error
+}
+
+func NewReader(rdb *redis.Client) *Reader {
+	return &Reader{rdb: rdb, followers: map[string]map[*follower]struct{}{}}
+}
+
+// Follow passes the entries of stream key after id after to fn, in order,
+// until ctx is done, fn fails or the stream cannot be read. Past the entries
+// already there, fn runs on the reader's goroutine with its lock held, so it
+// must return quickly and must not call back into the Reader.
+func (r *Reader) Follow(ctx context.Context, key, after string, fn func(redis.XMessage) error) error {
+	f := &follower{last: after, fn: fn, done: make(chan error, 1)}
+
+	// catch up without waiting, so a follower does not sit out the read
+	// already in flight
+	for {
+		streams, err := r.rdb.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{key, f.last},
+			Count:   readBatch,
+			Block:   -1,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		n := 0
+		for _, s := range streams {
+			for _, m := range s.Messages {
+				f.last = m.ID
+				if err := fn(m); err != nil {
+					return err
+				}
+				n++
+			}
+		}
+		if n < readBatch {
+			break
+		}
+	}
+
+	r.mu.Lock()
+	if r.followers[key] == nil {
+		r.followers[key] = map[*follower]struct{}{}
+	}
+	r.followers[key][f] = struct{}{}
+	if !r.running {
+		r.running = true
+		go r.run()
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		r.mu.Lock()
+		r.remove(key, f)
+		r.mu.Unlock()
+		return ctx.Err()
+	case err := <-f.done:
+		return err
+	}
+}
+
+// run reads every followed stream from its furthest behind follower until
+// nobody follows any.
+func (r *Reader) run() {
+	for {
+		r.mu.Lock()
+		if len(r.followers) == 0 {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		keys := make([]string, 0, len(r.followers))
+		ids := make([]string, 0, len(r.followers))
+		for key, fs := range r.followers {
+			from := ""
+			for f := range fs {
+				if from == "" || Before(f.last, from) {
+					from = f.last
+				}
+			}
+			keys = append(keys, key)
+			ids = append(ids, from)
+		}
+		r.mu.Unlock()
+
+		// the read outlives any one follower, so it is not bound to theirs
+		streams, err := r.rdb.XRead(context.Background(), &redis.XReadArgs{
+			Streams: append(keys, ids...),
+			Count:   readBatch,
+			Block:   readBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		r.mu.Lock()
+		if err != nil {
+			for key, fs := range r.followers {
+				for f := range fs {
+					r.stop(key, f, err)
+				}
+			}
+		}
+		for _, s := range streams {
+			for f := range r.followers[s.Stream] {
+				for _, m := range s.Messages {
+					if !Before(f.last, m.ID) {
+						continue
+					}
+					f.last = m.ID
+					if err := f.fn(m); err != nil {
+						r.stop(s.Stream, f, err)
+						break
+					}
+				}
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// stop ends f's Follow with err. Callers hold mu.
+func (r *Reader) stop(key string, f *follower, err error) {
+	r.remove(key, f)
+	f.done <- err
+}
+
+// remove drops f from key's followers. Callers hold mu.
+func (r *Reader) remove(key string, f *follower) {
+	delete(r.followers[key], f)
+	if len(r.followers[key]) == 0 {
+		delete(r.followers, key)
+	}
+}
+
+// Before reports whether stream entry id a comes before b. Ids that do not
+// parse sort first.
+func Before(a, b string) bool {
+	ams, aseq := splitID(a)
+	bms, bseq := splitID(b)
+	if ams != bms {
+		return ams < bms
+	}
+	return aseq < bseq
+}
+
+func splitID(id string) (ms, seq uint64) {
+	m, s, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(m, 10, 64)
+	seq, _ = strconv.ParseUint(s, 10, 64)
+	return ms, seq
+}
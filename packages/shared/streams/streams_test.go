@@ -0,0 +1,95 @@
+package streams_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/yaninyzwitty/chat/packages/shared/streams"
+)
+
+func TestFollowSharesOneReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	r := streams.NewReader(rdb)
+
+	add := func(key, id string) {
+		t.Helper()
+		require.NoError(t, rdb.XAdd(ctx, &redis.XAddArgs{Stream: key, ID: id, Values: []any{"v", id}}).Err())
+	}
+	// entries before the follow starts are caught up on
+	add("a", "0-1")
+	add("a", "0-2")
+
+	got := make(chan string, 8)
+	follow := func(key, after string) chan error {
+		done := make(chan error, 1)
+		go func() {
+			done <- r.Follow(ctx, key, after, func(m redis.XMessage) error {
+				got <- key + "/" + m.ID
+				return nil
+			})
+		}()
+		return done
+	}
+	next := func() string {
+		t.Helper()
+		select {
+		case s := <-got:
+			return s
+		case <-time.After(3 * time.Second):
+			t.Fatal("no entry passed on")
+			return ""
+		}
+	}
+
+	doneA := follow("a", "0-1")
+	require.Equal(t, "a/0-2", next())
+	doneB := follow("b", "0-0")
+
+	add("b", "0-1")
+	require.Equal(t, "b/0-1", next())
+	add("a", "0-3")
+	require.Equal(t, "a/0-3", next())
+
+	cancel()
+	require.ErrorIs(t, <-doneA, context.Canceled)
+	require.ErrorIs(t, <-doneB, context.Canceled)
+}
+
+func TestFollowStopsWhenFnFails(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	r := streams.NewReader(rdb)
+
+	errFull := errors.New("full")
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Follow(ctx, "a", "0-0", func(redis.XMessage) error { return errFull })
+	}()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, rdb.XAdd(ctx, &redis.XAddArgs{Stream: "a", ID: "0-1", Values: []any{"v", 1}}).Err())
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, errFull)
+	case <-time.After(3 * time.Second):
+		t.Fatal("follow did not stop")
+	}
+}
+
+func TestBefore(t *testing.T) {
+	require.True(t, streams.Before("0-2", "0-10"))
+	require.True(t, streams.Before("5-9", "6-0"))
+	require.False(t, streams.Before("6-0", "6-0"))
+	require.False(t, streams.Before("7-0", "6-9"))
+}
@@ -0,0 +1,38 @@
+// Package users answers whether user accounts exist. Like blocklist, it reads
+// Cassandra directly so other services can run the check on their own session
+// without a round trip through UserService.
+package users
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// Missing returns those of ids with no account, in the order given.
+func Missing(ctx context.Context, db *gocql.Session, ids []gocql.UUID) ([]gocql.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	iter := db.Query(`SELECT id FROM chat.users WHERE id IN ?`, ids).
+		WithContext(ctx).Consistency(gocql.One).Iter()
+
+	found := make(map[gocql.UUID]bool, len(ids))
+	var id gocql.UUID
+	for iter.Scan(&id) {
+		found[id] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+
+	var missing []gocql.UUID
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,180 @@
+// Package avatar turns uploaded profile images into sanitised square
+// thumbnails and stores them in a BlobStore.
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register decoder
+)
+
+const (
+	// DefaultMaxBytes bounds an upload when the config leaves it unset.
+	DefaultMaxBytes = 5 << 20
+	// MaxSize bounds the configurable thumbnail edge length.
+	MaxSize = 2048
+	// maxPixels rejects images whose decoded bitmap would be unreasonably large.
+	maxPixels   = 40_000_000
+	jpegQuality = 85
+)
+
+// DefaultSizes are the thumbnail edge lengths used when the config sets none.
+var DefaultSizes = []int{64, 128, 256, 512}
+
+// AllowedContentTypes are the image formats accepted for upload.
+var AllowedContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrInvalidImage    = errors.New("invalid image")
+)
+
+// Thumbnail is one re-encoded square rendition of an upload.
+type Thumbnail struct {
+	Size        int
+	ContentType string
+	Data        []byte
+}
+
+// Ext returns the file extension matching the thumbnail's encoding.
+func (t Thumbnail) Ext() string {
+	if t.ContentType == "image/jpeg" {
+		return "jpg"
+	}
+	return "png"
+}
+
+// CheckSizes rejects thumbnail sizes Process cannot render.
+func CheckSizes(sizes []int) error {
+	for _, size := range sizes {
+		if size <= 0 || size > MaxSize {
+			return fmt.Errorf("thumbnail size %d is outside 1..%d", size, MaxSize)
+		}
+	}
+	return nil
+}
+
+// Process sniffs and decodes data, centre-crops it to a square and renders one
+// thumbnail per size. Re-encoding from pixels drops EXIF and any other
+// metadata carried by the original file. Opaque images are encoded as JPEG,
+// anything with transparency as PNG.
+func Process(data []byte, sizes []int) ([]Thumbnail, error) {
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(AllowedContentTypes, contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: dimensions %dx%d out of range", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	square := centerSquare(src.Bounds())
+	opaque := isOpaque(src)
+
+	thumbs := make([]Thumbnail, 0, len(sizes))
+	for _, size := range sizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, square, draw.Src, nil)
+
+		var buf bytes.Buffer
+		thumb := Thumbnail{Size: size}
+		if opaque {
+			thumb.ContentType = "image/jpeg"
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			thumb.ContentType = "image/png"
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx thumbnail: %w", size, err)
+		}
+		thumb.Data = buf.Bytes()
+		thumbs = append(thumbs, thumb)
+	}
+
+	return thumbs, nil
+}
+
+// Key is the blob key of one thumbnail. version distinguishes uploads so keys
+// are immutable and can be cached indefinitely.
+func Key(userID, version string, t Thumbnail) string {
+	return fmt.Sprintf("%s/%d.%s", versionPrefix(userID, version), t.Size, t.Ext())
+}
+
+func versionPrefix(userID, version string) string { return "avatars/" + userID + "/" + version }
+
+// VersionOf returns the upload version key belongs to, if key is one of
+// userID's thumbnails.
+func VersionOf(userID, key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, "avatars/"+userID+"/")
+	if !ok {
+		return "", false
+	}
+	version, _, ok := strings.Cut(rest, "/")
+	return version, ok && version != ""
+}
+
+// Remove deletes every thumbnail of one upload.
+func Remove(ctx context.Context, store BlobStore, userID, version string) error {
+	return store.DeleteAll(ctx, versionPrefix(userID, version))
+}
+
+// Save writes every thumbnail to store and returns their keys in order.
+func Save(ctx context.Context, store BlobStore, userID, version string, thumbs []Thumbnail) ([]string, error) {
+	keys := make([]string, 0, len(thumbs))
+	for _, t := range thumbs {
+		key := Key(userID, version, t)
+		if err := store.Put(ctx, key, t.Data); err != nil {
+			// don't leave a half-written version behind
+			for _, k := range keys {
+				_ = store.Delete(ctx, k)
+			}
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// centerSquare returns the largest square centred within b.
+func centerSquare(b image.Rectangle) image.Rectangle {
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	return image.Rect(x0, y0, x0+side, y0+side)
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package avatar_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yaninyzwitty/chat/packages/user/avatar"
+)
+
+func encodePNG(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	testCases := []struct {
+		name       string
+		data       []byte
+		expectType string
+		errors     bool
+	}{
+		{
+			name:       "success:opaque_png_becomes_jpeg",
+			data:       encodePNG(t, 300, 200, color.NRGBA{R: 200, A: 255}),
+			expectType: "image/jpeg",
+		},
+		{
+			name:       "success:transparent_png_stays_png",
+			data:       encodePNG(t, 120, 160, color.NRGBA{G: 100, A: 128}),
+			expectType: "image/png",
+		},
+		{
+			name:   "error:not_an_image",
+			data:   []byte("hello, world"),
+			errors: true,
+		},
+		{
+			name:   "error:truncated_png",
+			data:   encodePNG(t, 64, 64, color.White)[:40],
+			errors: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			thumbs, err := avatar.Process(tc.data, []int{32, 64})
+			if tc.errors {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, thumbs, 2)
+			for i, size := range []int{32, 64} {
+				require.Equal(t, size, thumbs[i].Size)
+				require.Equal(t, tc.expectType, thumbs[i].ContentType)
+
+				cfg, _, err := image.DecodeConfig(bytes.NewReader(thumbs[i].Data))
+				require.NoError(t, err)
+				require.Equal(t, size, cfg.Width)
+				require.Equal(t, size, cfg.Height)
+			}
+		})
+	}
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := avatar.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	thumbs, err := avatar.Process(encodePNG(t, 10, 10, color.Black), []int{16})
+	require.NoError(t, err)
+
+	keys, err := avatar.Save(ctx, store, "user-1", "v1", thumbs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"avatars/user-1/v1/16.jpg"}, keys)
+
+	blob, err := store.Get(ctx, keys[0])
+	require.NoError(t, err)
+	data, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	require.Equal(t, thumbs[0].Data, data)
+
+	require.NoError(t, store.Delete(ctx, keys[0]))
+	_, err = store.Get(ctx, keys[0])
+	require.ErrorIs(t, err, avatar.ErrNotFound)
+
+	for _, key := range []string{"../escape", "avatars/../../etc/passwd", "/abs", ""} {
+		require.Error(t, store.Put(ctx, key, []byte("x")), key)
+	}
+}
+
+func TestRemoveVersion(t *testing.T) {
+	ctx := context.Background()
+	store, err := avatar.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	thumbs, err := avatar.Process(encodePNG(t, 10, 10, color.Black), []int{16, 32})
+	require.NoError(t, err)
+	old, err := avatar.Save(ctx, store, "user-1", "v1", thumbs)
+	require.NoError(t, err)
+	current, err := avatar.Save(ctx, store, "user-1", "v2", thumbs)
+	require.NoError(t, err)
+
+	version, ok := avatar.VersionOf("user-1", old[1])
+	require.True(t, ok)
+	require.Equal(t, "v1", version)
+	_, ok = avatar.VersionOf("user-2", old[1])
+	require.False(t, ok)
+
+	require.NoError(t, avatar.Remove(ctx, store, "user-1", version))
+	for _, key := range old {
+		_, err := store.Get(ctx, key)
+		require.ErrorIs(t, err, avatar.ErrNotFound)
+	}
+	blob, err := store.Get(ctx, current[0])
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+}
+
+func TestCheckSizes(t *testing.T) {
+	require.NoError(t, avatar.CheckSizes(avatar.DefaultSizes))
+	require.Error(t, avatar.CheckSizes([]int{64, 0}))
+	require.Error(t, avatar.CheckSizes([]int{-1}))
+	require.Error(t, avatar.CheckSizes([]int{avatar.MaxSize + 1}))
+}
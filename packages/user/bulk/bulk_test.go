@@ -0,0 +1,178 @@
+package bulk_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/bulk"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// memStore is an in-memory bulk.Store.
+type memStore struct {
+	mu     sync.Mutex
+	users  map[string]string // email -> hash
+	ids    map[string]bool
+	failAt string
+}
+
+func newMemStore(emails ...string) *memStore {
+	s := &memStore{users: make(map[string]string), ids: make(map[string]bool)}
+	for _, e := range emails {
+		s.users[e] = "existing"
+	}
+	return s
+}
+
+func (s *memStore) EmailExists(_ context.Context, email string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.users[email]
+	return ok, nil
+}
+
+func (s *memStore) ImportUser(_ context.Context, user *userv1.User, hash string) error {
+	if user.Email == s.failAt {
+		return errors.New("write failed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids[user.Id] {
+		return status.Errorf(codes.AlreadyExists, "user %s already exists", user.Id)
+	}
+	s.ids[user.Id] = true
+	s.users[user.Email] = hash
+	return nil
+}
+
+func hash(t *testing.T) string {
+	h, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(h)
+}
+
+func TestImportCSV(t *testing.T) {
+	h := hash(t)
+	input := "name,alias_name,email,password_hash\n" +
+		"Alice,ali,Alice@Example.com," + h + "\n" +
+		"Bob,bob,bob@example.com," + h + "\n" +
+		"Alice Again,ali2, Alice@Example.com ," + h + "\n" + // duplicate once trimmed
+		"Carol,caz,not-an-email," + h + "\n" +
+		"Dan,dan,dan@example.com,plaintext\n" + // not a bcrypt hash
+		"Eve,eve,eve@example.com," + h + "\n"
+
+	r, err := bulk.NewReader(strings.NewReader(input), bulk.CSV)
+	require.NoError(t, err)
+
+	store := newMemStore("eve@example.com")
+	cp, err := bulk.LoadCheckpoint(filepath.Join(t.TempDir(), "cp"), "users.csv")
+	require.NoError(t, err)
+
+	var skipped []int
+	var mu sync.Mutex
+	stats, err := bulk.Import(context.Background(), r, store, cp, bulk.Options{
+		Concurrency: 2,
+		Skipped: func(line int, _ error) {
+			mu.Lock()
+			skipped = append(skipped, line)
+			mu.Unlock()
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, bulk.Stats{Imported: 2, Existing: 1, Duplicate: 1, Invalid: 2}, stats)
+	require.ElementsMatch(t, []int{3, 4, 5, 6}, skipped)
+	require.Equal(t, h, store.users["Alice@Example.com"])
+	require.Equal(t, 6, cp.Line())
+}
+
+func TestImportSkipsTakenIDs(t *testing.T) {
+	h := hash(t)
+	const id = "5c2f1a4e-8d3b-11ee-b9d1-0242ac120002"
+	input := `{"id":"` + id + `","name":"Alice","alias_name":"ali","email":"alice@example.com","password_hash":"` + h + `"}
+{"id":"` + id + `","name":"Bob","alias_name":"bob","email":"bob@example.com","password_hash":"` + h + `"}
+{"name":"Carol","alias_name":"caz","email":"carol@example.com","password_hash":"` + h + `"}
+`
+	r, err := bulk.NewReader(strings.NewReader(input), bulk.JSONL)
+	require.NoError(t, err)
+	cp, err := bulk.LoadCheckpoint(filepath.Join(t.TempDir(), "cp"), "users.jsonl")
+	require.NoError(t, err)
+
+	var reasons []error
+	stats, err := bulk.Import(context.Background(), r, newMemStore(), cp, bulk.Options{
+		Concurrency: 1,
+		Skipped:     func(_ int, reason error) { reasons = append(reasons, reason) },
+	})
+	require.NoError(t, err)
+	require.Equal(t, bulk.Stats{Imported: 2, Existing: 1}, stats)
+	require.Len(t, reasons, 1)
+	require.ErrorIs(t, reasons[0], bulk.ErrExists)
+	require.Equal(t, 3, cp.Line())
+}
+
+func TestImportResumesFromCheckpoint(t *testing.T) {
+	h := hash(t)
+	input := `{"name":"Alice","alias_name":"ali","email":"alice@example.com","password_hash":"` + h + `"}
+{"name":"Bob","alias_name":"bob","email":"bob@example.com","password_hash":"` + h + `"}
+{"name":"Carol","alias_name":"caz","email":"carol@example.com","password":"hunter2"}
+`
+	cpPath := filepath.Join(t.TempDir(), "cp")
+	store := newMemStore()
+	store.failAt = "bob@example.com"
+
+	// first run stops at Bob
+	cp, err := bulk.LoadCheckpoint(cpPath, "users.jsonl")
+	require.NoError(t, err)
+	r, err := bulk.NewReader(strings.NewReader(input), bulk.JSONL)
+	require.NoError(t, err)
+	_, err = bulk.Import(context.Background(), r, store, cp, bulk.Options{Concurrency: 1})
+	require.Error(t, err)
+	require.Equal(t, 1, cp.Line())
+
+	// second run skips Alice and finishes
+	store.failAt = ""
+	cp, err = bulk.LoadCheckpoint(cpPath, "users.jsonl")
+	require.NoError(t, err)
+	require.Equal(t, 1, cp.Line())
+	r, err = bulk.NewReader(strings.NewReader(input), bulk.JSONL)
+	require.NoError(t, err)
+	stats, err := bulk.Import(context.Background(), r, store, cp, bulk.Options{Concurrency: 1})
+	require.NoError(t, err)
+	require.Equal(t, bulk.Stats{Imported: 2, Resumed: 1}, stats)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(store.users["carol@example.com"]), []byte("hunter2")))
+
+	// a checkpoint is tied to its input file
+	_, err = bulk.LoadCheckpoint(cpPath, "other.jsonl")
+	require.Error(t, err)
+}
+
+func TestCheckpointWatermark(t *testing.T) {
+	cp, err := bulk.LoadCheckpoint(filepath.Join(t.TempDir(), "cp"), "in.csv")
+	require.NoError(t, err)
+
+	cp.Done(2)
+	cp.Done(3)
+	require.Equal(t, 0, cp.Line())
+	cp.Done(1)
+	require.Equal(t, 3, cp.Line())
+}
+
+func TestExporterOmitsNothingSecret(t *testing.T) {
+	var sb strings.Builder
+	e := bulk.NewExporter(&sb)
+	require.NoError(t, e.Write(&userv1.User{Id: "1", Name: "Alice", AliasName: "ali", Email: "alice@example.com"}))
+	require.NoError(t, e.Write(&userv1.User{Id: "2", Name: "Bob"}))
+	require.NoError(t, e.Flush())
+
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"alias_name"`)
+	require.NotContains(t, sb.String(), "password")
+}
@@ -0,0 +1,108 @@
+package bulk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint tracks how far an import has got so a rerun can skip records
+// already handled. Records finish out of order under concurrency, so it keeps
+// a low watermark: every record up to Line is done.
+type Checkpoint struct {
+	path   string
+	source string
+
+	mu      sync.Mutex
+	line    int
+	pending map[int]bool
+}
+
+type checkpointFile struct {
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+}
+
+// LoadCheckpoint opens the checkpoint at path for importing source, starting
+// from scratch if none exists. A checkpoint written for a different source is
+// rejected rather than silently applied.
+func LoadCheckpoint(path, source string) (*Checkpoint, error) {
+	c := &Checkpoint{path: path, source: source, pending: make(map[int]bool)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var f checkpointFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if f.Source != source {
+		return nil, fmt.Errorf("checkpoint %s belongs to %q, not %q", path, f.Source, source)
+	}
+	c.line = f.Line
+	return c, nil
+}
+
+// Line is the last record such that it and every record before it are done.
+func (c *Checkpoint) Line() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.line
+}
+
+// Done marks record line as handled, whatever the outcome.
+func (c *Checkpoint) Done(line int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if line <= c.line {
+		return
+	}
+	c.pending[line] = true
+	for c.pending[c.line+1] {
+		delete(c.pending, c.line+1)
+		c.line++
+	}
+}
+
+// Save atomically writes the current watermark to disk.
+func (c *Checkpoint) Save() error {
+	data, err := json.Marshal(checkpointFile{Source: c.source, Line: c.Line()})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint once an import has finished.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package bulk
+
+import (
+	"bufio"
+	"io"
+
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Exporter writes users as JSONL. Password hashes are never part of a
+// userv1.User, so they cannot leak into an export. The output can be fed
+// back to Import once password fields are added.
+type Exporter struct {
+	w    *bufio.Writer
+	opts protojson.MarshalOptions
+}
+
+// NewExporter returns an Exporter writing to w; call Flush when done.
+func NewExporter(w io.Writer) *Exporter {
+	return &Exporter{
+		w:    bufio.NewWriter(w),
+		opts: protojson.MarshalOptions{UseProtoNames: true},
+	}
+}
+
+// Write appends u as one line.
+func (e *Exporter) Write(u *userv1.User) error {
+	data, err := e.opts.Marshal(u)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+// Flush writes any buffered output.
+func (e *Exporter) Flush() error {
+	return e.w.Flush()
+}
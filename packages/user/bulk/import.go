@@ -0,0 +1,207 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultConcurrency = 8
+	DefaultSaveEvery   = 100
+)
+
+var (
+	// ErrDuplicate reports a record whose email appeared earlier in the file.
+	ErrDuplicate = errors.New("duplicate email in input")
+	// ErrExists reports a record for a user the store already has, by email
+	// or by id.
+	ErrExists = errors.New("user already exists")
+)
+
+// Store is where imported users are written.
+type Store interface {
+	EmailExists(ctx context.Context, email string) (bool, error)
+	// ImportUser fails with codes.AlreadyExists when the user's id is taken.
+	ImportUser(ctx context.Context, user *userv1.User, passwordHash string) error
+}
+
+// Options tune an import.
+type Options struct {
+	// Concurrency bounds the records being written at once.
+	Concurrency int
+	// SaveEvery is how many records are read between checkpoint saves.
+	SaveEvery int
+	// Skipped, if set, is told about every record not imported and why.
+	Skipped func(line int, reason error)
+}
+
+// Stats counts what happened to each record of an import.
+type Stats struct {
+	Imported  int
+	Existing  int
+	Duplicate int
+	Invalid   int
+	// Resumed is the number of records skipped as done by a previous run.
+	Resumed int
+}
+
+// Import reads every record from r and writes the new ones to store,
+// recording progress in cp. Records that are malformed, repeat an earlier
+// email or belong to an existing user, by email or by id, are skipped; a
+// failure to write stops the import with cp at the last record known to be
+// handled.
+func Import(ctx context.Context, r Reader, store Store, cp *Checkpoint, opts Options) (Stats, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.SaveEvery <= 0 {
+		opts.SaveEvery = DefaultSaveEvery
+	}
+
+	var (
+		mu    sync.Mutex
+		stats Stats
+	)
+	count := func(field *int) {
+		mu.Lock()
+		*field++
+		mu.Unlock()
+	}
+	skip := func(line int, field *int, reason error) {
+		count(field)
+		if opts.Skipped != nil {
+			opts.Skipped(line, reason)
+		}
+		cp.Done(line)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.Concurrency)
+
+	// email -> first record that used it, including records a previous run
+	// already handled, so duplicates are caught across resumes too
+	seen := make(map[string]int)
+	resumeAfter := cp.Line()
+
+	var readErr error
+	for n := 1; gctx.Err() == nil; n++ {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		var recErr *RecordError
+		if errors.As(err, &recErr) {
+			if recErr.Line <= resumeAfter {
+				stats.Resumed++
+			} else {
+				skip(recErr.Line, &stats.Invalid, recErr)
+			}
+			continue
+		}
+		if err != nil {
+			readErr = fmt.Errorf("failed to read input: %w", err)
+			break
+		}
+
+		rec.Normalize()
+		validErr := rec.Validate()
+		first, dup := seen[rec.Email]
+		if validErr == nil && !dup {
+			seen[rec.Email] = rec.Line
+		}
+
+		switch {
+		case rec.Line <= resumeAfter:
+			stats.Resumed++
+		case validErr != nil:
+			skip(rec.Line, &stats.Invalid, validErr)
+		case dup:
+			skip(rec.Line, &stats.Duplicate, fmt.Errorf("%w, first seen in record %d", ErrDuplicate, first))
+		default:
+			g.Go(func() error {
+				// another record has failed; leave the rest for the rerun
+				if err := gctx.Err(); err != nil {
+					return err
+				}
+				err := importRecord(gctx, store, rec)
+				switch {
+				case errors.Is(err, ErrExists):
+					skip(rec.Line, &stats.Existing, err)
+				case err != nil:
+					return fmt.Errorf("record %d: %w", rec.Line, err)
+				default:
+					count(&stats.Imported)
+					cp.Done(rec.Line)
+				}
+				return nil
+			})
+		}
+
+		if n%opts.SaveEvery == 0 {
+			if err := cp.Save(); err != nil {
+				readErr = err
+				break
+			}
+		}
+	}
+
+	err := errors.Join(g.Wait(), readErr)
+	if saveErr := cp.Save(); saveErr != nil {
+		err = errors.Join(err, saveErr)
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return stats, err
+}
+
+// importRecord writes rec, or fails with ErrExists when its email or id is
+// already taken.
+func importRecord(ctx context.Context, store Store, rec Record) error {
+	exists, err := store.EmailExists(ctx, rec.Email)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w with this email", ErrExists)
+	}
+
+	hash, err := rec.Hash()
+	if err != nil {
+		return err
+	}
+
+	id := rec.ID
+	if id == "" {
+		id = gocql.TimeUUID().String()
+	}
+	createdAt := rec.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	user := &userv1.User{
+		Id:        id,
+		Name:      rec.Name,
+		AliasName: rec.AliasName,
+		Email:     rec.Email,
+		CreatedAt: timestamppb.New(createdAt),
+		UpdatedAt: timestamppb.Now(),
+	}
+	err = store.ImportUser(ctx, user, hash)
+	if status.Code(err) == codes.AlreadyExists {
+		return fmt.Errorf("%w with id %s", ErrExists, id)
+	}
+	return err
+}
@@ -0,0 +1,232 @@
+// Package bulk imports users from CSV or JSONL files and exports them to
+// JSONL, for migrating an existing user base into chat.users.
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Format is the encoding of an import file.
+type Format int
+
+const (
+	CSV Format = iota + 1
+	JSONL
+)
+
+// FormatFromPath infers the format from the file extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV, nil
+	case ".jsonl", ".ndjson":
+		return JSONL, nil
+	}
+	return 0, fmt.Errorf("cannot infer format of %q, expected .csv or .jsonl", path)
+}
+
+// Record is one user read from an import file.
+type Record struct {
+	// Line is the 1-based position of the record in the file, not counting
+	// the CSV header.
+	Line int `json:"-"`
+
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	AliasName string `json:"alias_name"`
+	Email     string `json:"email"`
+	// PasswordHash is an existing bcrypt hash, imported as is.
+	PasswordHash string `json:"password_hash"`
+	// Password is a plaintext password, hashed on import when no
+	// PasswordHash is given.
+	Password  string    `json:"password"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Normalize trims fields. The email keeps its case, as sign-up and login
+// compare emails exactly.
+func (r *Record) Normalize() {
+	r.ID = strings.TrimSpace(r.ID)
+	r.Name = strings.TrimSpace(r.Name)
+	r.AliasName = strings.TrimSpace(r.AliasName)
+	r.Email = strings.TrimSpace(r.Email)
+	r.PasswordHash = strings.TrimSpace(r.PasswordHash)
+}
+
+// Validate reports the first problem with a normalized record.
+func (r *Record) Validate() error {
+	if r.Name == "" || r.AliasName == "" || r.Email == "" {
+		return errors.New("name, alias_name and email are required")
+	}
+	if addr, err := mail.ParseAddress(r.Email); err != nil || addr.Address != r.Email {
+		return fmt.Errorf("invalid email %q", r.Email)
+	}
+	if r.ID != "" {
+		if _, err := gocql.ParseUUID(r.ID); err != nil {
+			return fmt.Errorf("invalid id %q: %v", r.ID, err)
+		}
+	}
+	switch {
+	case r.PasswordHash != "":
+		if _, err := bcrypt.Cost([]byte(r.PasswordHash)); err != nil {
+			return fmt.Errorf("password_hash is not a bcrypt hash: %v", err)
+		}
+	case r.Password == "":
+		return errors.New("password_hash or password is required")
+	}
+	return nil
+}
+
+// Hash returns the bcrypt hash to store, hashing Password if needed.
+func (r *Record) Hash() (string, error) {
+	if r.PasswordHash != "" {
+		return r.PasswordHash, nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(r.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Reader yields records from an import file, returning io.EOF when done. A
+// *RecordError means only that record was unreadable and reading may go on.
+type Reader interface {
+	Next() (Record, error)
+}
+
+// RecordError is a problem with a single record.
+type RecordError struct {
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error { return e.Err }
+
+// NewReader returns a Reader decoding r in format f.
+func NewReader(r io.Reader, f Format) (Reader, error) {
+	switch f {
+	case CSV:
+		return newCSVReader(r)
+	case JSONL:
+		return &jsonlReader{scanner: newLineScanner(r)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %d", f)
+}
+
+// csvColumns are the recognised CSV header names.
+var csvColumns = []string{"id", "name", "alias_name", "email", "password_hash", "password", "created_at"}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns []string
+	line    int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i, col := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(col))
+		if !slices.Contains(csvColumns, header[i]) {
+			return nil, fmt.Errorf("unknown CSV column %q", col)
+		}
+	}
+	cr.FieldsPerRecord = len(header)
+
+	return &csvReader{r: cr, columns: header}, nil
+}
+
+func (c *csvReader) Next() (Record, error) {
+	fields, err := c.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			c.line++
+			return Record{Line: c.line}, &RecordError{Line: c.line, Err: parseErr.Err}
+		}
+		return Record{}, err
+	}
+	c.line++
+
+	rec := Record{Line: c.line}
+	for i, col := range c.columns {
+		v := fields[i]
+		switch col {
+		case "id":
+			rec.ID = v
+		case "name":
+			rec.Name = v
+		case "alias_name":
+			rec.AliasName = v
+		case "email":
+			rec.Email = v
+		case "password_hash":
+			rec.PasswordHash = v
+		case "password":
+			rec.Password = v
+		case "created_at":
+			if v == "" {
+				continue
+			}
+			if rec.CreatedAt, err = time.Parse(time.RFC3339, v); err != nil {
+				return rec, &RecordError{Line: c.line, Err: fmt.Errorf("invalid created_at %q", v)}
+			}
+		}
+	}
+	return rec, nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (j *jsonlReader) Next() (Record, error) {
+	for j.scanner.Scan() {
+		text := strings.TrimSpace(j.scanner.Text())
+		if text == "" {
+			continue
+		}
+		j.line++
+
+		rec := Record{}
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return Record{Line: j.line}, &RecordError{Line: j.line, Err: err}
+		}
+		rec.Line = j.line
+		return rec, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	return s
+}
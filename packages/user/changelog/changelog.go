@@ -0,0 +1,198 @@
+// Package changelog defines the user change log, an append-only record of
+// user mutations partitioned into hourly buckets, and the Watcher that
+// replays it from a cursor and then tails new entries.
+package changelog
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/gocql/gocql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Retention matches the default_time_to_live of the user_changes table;
+// cursors older than this can no longer be replayed.
+const Retention = 7 * 24 * time.Hour
+
+// BucketWidth is the span of time covered by one user_changes partition.
+const BucketWidth = time.Hour
+
+const (
+	DefaultPollInterval = time.Second
+	// DefaultLookback covers entries that commit after later ones, e.g. when
+	// instance clocks disagree or a write is slow to land.
+	DefaultLookback = 5 * time.Second
+)
+
+type Kind string
+
+const (
+	KindCreated     Kind = "created"
+	KindUpdated     Kind = "updated"
+	KindSuspended   Kind = "suspended"
+	KindUnsuspended Kind = "unsuspended"
+)
+
+// Entry is a single change. Its ID is a time UUID ordering it within the log
+// and doubling as the cursor to resume after it.
+type Entry struct {
+	ID     gocql.UUID
+	UserID gocql.UUID
+	Kind   Kind
+	// changed user fields, for updates
+	Fields []string
+}
+
+// Bucket names the partition holding changes made at t.
+func Bucket(t time.Time) string {
+	return t.UTC().Truncate(BucketWidth).Format("2006-01-02T15")
+}
+
+// EncodeCursor returns the opaque cursor resuming after id.
+func EncodeCursor(id gocql.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id.Bytes())
+}
+
+// DecodeCursor parses a cursor returned by EncodeCursor.
+func DecodeCursor(cursor string) (gocql.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return gocql.UUID{}, status.Error(codes.InvalidArgument, "malformed cursor")
+	}
+	id, err := gocql.UUIDFromBytes(raw)
+	if err != nil || id.Version() != 1 {
+		return gocql.UUID{}, status.Error(codes.InvalidArgument, "malformed cursor")
+	}
+	return id, nil
+}
+
+// ScanFunc calls fn, oldest first, with every entry in bucket ordered after
+// the time UUID after.
+type ScanFunc func(ctx context.Context, bucket string, after gocql.UUID, fn func(Entry) error) error
+
+// Watcher replays and tails the change log by polling, since Cassandra has no
+// way to push new rows. Delivery is at least once: an entry may repeat when
+// it shares a timestamp with the cursor it resumes from.
+type Watcher struct {
+	Scan ScanFunc
+	// time between polls once the replay has caught up
+	PollInterval time.Duration
+	// how far back each poll rescans for late entries
+	Lookback time.Duration
+	Now      func() time.Time
+}
+
+// Watch calls fn with every entry after cursor, or with entries from now on
+// when cursor is the zero UUID, until ctx is done or fn fails.
+func (w *Watcher) Watch(ctx context.Context, cursor gocql.UUID, fn func(Entry) error) error {
+	now := w.now()
+
+	from := now
+	if cursor != (gocql.UUID{}) {
+		from = cursor.Time()
+		if now.Sub(from) > Retention {
+			return status.Error(codes.OutOfRange, "cursor is older than the change log retention; resync and watch from now")
+		}
+	}
+
+	// entries recent enough for the next poll to rescan, so it can skip them
+	seen := make(map[gocql.UUID]time.Time)
+	var horizon time.Time
+
+	deliver := func(e Entry) error {
+		at := e.ID.Time()
+		if at.Before(from) || e.ID == cursor {
+			return nil
+		}
+		if _, ok := seen[e.ID]; ok {
+			return nil
+		}
+		if !at.Before(horizon) {
+			seen[e.ID] = at
+		}
+		return fn(e)
+	}
+
+	// replay from the cursor up to now
+	horizon = now.Add(-w.lookback())
+	after := cursor
+	if cursor == (gocql.UUID{}) {
+		after = gocql.MinTimeUUID(from)
+	}
+	if err := w.scanRange(ctx, after, now, deliver); err != nil {
+		return ignoreDone(ctx, err)
+	}
+	last := now
+
+	ticker := time.NewTicker(w.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// rescan from the previous poll so a slow scan leaves no gap
+		lower := last.Add(-w.lookback())
+		for id, at := range seen {
+			if at.Before(lower) {
+				delete(seen, id)
+			}
+		}
+
+		now = w.now()
+		horizon = now.Add(-w.lookback())
+		if err := w.scanRange(ctx, gocql.MinTimeUUID(lower), now, deliver); err != nil {
+			return ignoreDone(ctx, err)
+		}
+		last = now
+	}
+}
+
+// scanRange scans every bucket from the one holding after through the one
+// holding until.
+func (w *Watcher) scanRange(ctx context.Context, after gocql.UUID, until time.Time, fn func(Entry) error) error {
+	for b := after.Time().UTC().Truncate(BucketWidth); !b.After(until); b = b.Add(BucketWidth) {
+		if err := w.Scan(ctx, Bucket(b), after, fn); err != nil {
+			return err
+		}
+		// later buckets are read from their start
+		after = gocql.MinTimeUUID(b.Add(BucketWidth))
+	}
+	return nil
+}
+
+// ignoreDone swallows err once ctx is done, since the watcher stopping is
+// then expected rather than a failure.
+func ignoreDone(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (w *Watcher) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+func (w *Watcher) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return DefaultPollInterval
+}
+
+func (w *Watcher) lookback() time.Duration {
+	if w.Lookback > 0 {
+		return w.Lookback
+	}
+	return DefaultLookback
+}
@@ -0,0 +1,54 @@
+package main
+
+import "sync"
+
+// liveStreams tracks long-lived responses, which http.Server.Shutdown either
+// cannot see (upgraded sockets) or would wait on until its deadline.
+type liveStreams struct {
+	closing chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newLiveStreams() *liveStreams {
+	return &liveStreams{closing: make(chan struct{})}
+}
+
+// Close tells every open stream the server is going away and refuses new
+// ones.
+func (l *liveStreams) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.closing)
+	}
+}
+
+// Closing is closed once the server starts shutting down.
+func (l *liveStreams) Closing() <-chan struct{} {
+	return l.closing
+}
+
+// track counts a stream about to open, unless the server is shutting down.
+// Each successful call must be paired with done.
+func (l *liveStreams) track() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return false
+	}
+	l.wg.Add(1)
+	return true
+}
+
+func (l *liveStreams) done() {
+	l.wg.Done()
+}
+
+// Wait blocks until every stream has ended.
+func (l *liveStreams) Wait() {
+	l.wg.Wait()
+}
@@ -6,21 +6,32 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
 	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/joho/godotenv"
 	"github.com/rs/cors"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
 	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
 	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"github.com/yaninyzwitty/chat/packages/shared/redisclient"
+	"github.com/yaninyzwitty/chat/packages/user/avatar"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var logger *slog.Logger
@@ -53,6 +64,24 @@ func runClient(ctx context.Context, logger *slog.Logger) error {
 		}
 	}
 
+	if err := godotenv.Load(); err != nil {
+		slog.Warn("Failed to load .env")
+	}
+
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		return errors.New("REDIS_URL environment variable is not set")
+	}
+	redisClient, err := redisclient.Connect(redisURL)
+	if err != nil {
+		return fmt.Errorf("failed to create redis client: %w", err)
+	}
+	defer func() {
+		if err := redisClient.Close(); err != nil {
+			slog.Warn("failed to close redis client", "error", err)
+		}
+	}()
+
 	// REST router
 	mux := http.NewServeMux()
 
@@ -71,6 +100,23 @@ func runClient(ctx context.Context, logger *slog.Logger) error {
 
 	userClient := userv1.NewUserServiceClient(userConn)
 
+	realtimeAddr := fmt.Sprintf("localhost:%d", cfg.RealtimePort)
+	realtimeConn, err := grpc.NewClient(realtimeAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to dial grpc realtime server: %w", err)
+	}
+	defer func() {
+		if cerr := realtimeConn.Close(); cerr != nil {
+			logger.Warn("grpc conn close error", slog.String("error", cerr.Error()))
+		}
+	}()
+
+	live := newLiveStreams()
+	// live events for browsers, which cannot speak gRPC streaming
+	mux.Handle("GET /ws", newWSGateway(realtimev1.NewRealtimeServiceClient(realtimeConn), live))
+	// the same events one way, for clients that only need to listen
+	mux.Handle("GET /events", newSSEHandler(events.NewLog(redisClient, events.FromConfig(cfg.Events)), live))
+
 	// Health route
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		if _, err := w.Write([]byte("OK")); err != nil {
@@ -167,10 +213,10 @@ func runClient(ctx context.Context, logger *slog.Logger) error {
 
 		grpcReq := &userv1.ListUsersRequest{
 			PageLimit: pageLimit,
-			PageToken: []byte(pageToken),
+			PageToken: pageToken,
 		}
 
-		resp, err := userClient.ListUsers(r.Context(), grpcReq)
+		resp, err := userClient.ListUsers(outgoingContext(r), grpcReq)
 		if err != nil {
 			st, ok := status.FromError(err)
 			if ok {
@@ -184,18 +230,197 @@ func runClient(ctx context.Context, logger *slog.Logger) error {
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(map[string]any{
 			"users":      resp.Users,
-			"page_token": string(resp.PageToken),
+			"page_token": resp.PageToken,
+		}); err != nil {
+			slog.Error("failed to encode JSON response", "error", err)
+		}
+	})
+
+	mux.HandleFunc("POST /users:batchGet", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "invalid json body", http.StatusBadRequest)
+			return
+		}
+
+		resp, err := userClient.BatchGetUsers(outgoingContext(r), &userv1.BatchGetUsersRequest{Ids: payload.IDs})
+		if err != nil {
+			st, ok := status.FromError(err)
+			if ok {
+				http.Error(w, st.Message(), httpStatusFromGrpc(st.Code()))
+				return
+			}
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		missing := resp.GetMissingIds()
+		if missing == nil {
+			missing = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"users":       resp.Users,
+			"missing_ids": missing,
 		}); err != nil {
 			slog.Error("failed to encode JSON response", "error", err)
 		}
 	})
 
+	mux.HandleFunc("PATCH /users/{id}/profile", func(w http.ResponseWriter, r *http.Request) {
+		// only the keys present in the body are updated
+		var payload struct {
+			Name            *string `json:"name"`
+			AliasName       *string `json:"alias_name"`
+			Bio             *string `json:"bio"`
+			StatusText      *string `json:"status_text"`
+			StatusEmoji     *string `json:"status_emoji"`
+			StatusExpiresAt *string `json:"status_expires_at"`
+			Timezone        *string `json:"timezone"`
+			Locale          *string `json:"locale"`
+			AvatarURL       *string `json:"avatar_url"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "invalid json body", http.StatusBadRequest)
+			return
+		}
+
+		profile := &userv1.User{}
+		var paths []string
+		setString := func(path string, value *string, dst *string) {
+			if value != nil {
+				*dst = *value
+				paths = append(paths, path)
+			}
+		}
+		setString("name", payload.Name, &profile.Name)
+		setString("alias_name", payload.AliasName, &profile.AliasName)
+		setString("bio", payload.Bio, &profile.Bio)
+		setString("status_text", payload.StatusText, &profile.StatusText)
+		setString("status_emoji", payload.StatusEmoji, &profile.StatusEmoji)
+		setString("timezone", payload.Timezone, &profile.Timezone)
+		setString("locale", payload.Locale, &profile.Locale)
+		setString("avatar_url", payload.AvatarURL, &profile.AvatarUrl)
+		if payload.StatusExpiresAt != nil {
+			if *payload.StatusExpiresAt != "" {
+				expiresAt, err := time.Parse(time.RFC3339, *payload.StatusExpiresAt)
+				if err != nil {
+					http.Error(w, "status_expires_at must be an RFC 3339 timestamp", http.StatusBadRequest)
+					return
+				}
+				profile.StatusExpiresAt = timestamppb.New(expiresAt)
+			}
+			paths = append(paths, "status_expires_at")
+		}
+		if len(paths) == 0 {
+			http.Error(w, "no profile fields to update", http.StatusBadRequest)
+			return
+		}
+
+		resp, err := userClient.UpdateProfile(outgoingContext(r), &userv1.UpdateProfileRequest{
+			Id:         r.PathValue("id"),
+			Profile:    profile,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+		})
+		if err != nil {
+			st, ok := status.FromError(err)
+			if ok {
+				http.Error(w, st.Message(), httpStatusFromGrpc(st.Code()))
+				return
+			}
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp.User); err != nil {
+			slog.Error("failed to encode JSON response", "error", err)
+		}
+	})
+
+	// read-only view of the directory the user service uploads into, so the
+	// proxy must run where that directory is mounted
+	blobs, err := avatar.NewLocalBlobStore(cfg.Avatar.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to open avatar store: %w", err)
+	}
+
+	mux.HandleFunc("GET /avatars/{key...}", func(w http.ResponseWriter, r *http.Request) {
+		key := "avatars/" + r.PathValue("key")
+
+		blob, err := blobs.Get(r.Context(), key)
+		if errors.Is(err, avatar.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, "invalid avatar key", http.StatusBadRequest)
+			return
+		}
+		defer func() {
+			if cerr := blob.Close(); cerr != nil {
+				slog.Warn("failed to close avatar blob", "error", cerr)
+			}
+		}()
+
+		// keys are versioned per upload, so their content never changes
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		if rs, ok := blob.(io.ReadSeeker); ok {
+			http.ServeContent(w, r, key, time.Time{}, rs)
+			return
+		}
+		w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(key)))
+		if _, err := io.Copy(w, blob); err != nil {
+			slog.Error("failed to write avatar", "error", err)
+		}
+	})
+
+	mux.HandleFunc("GET /me/export", func(w http.ResponseWriter, r *http.Request) {
+		stream, err := userClient.ExportMyData(outgoingContext(r), &userv1.ExportMyDataRequest{})
+		if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		// headers can only be set once the first chunk proves the export started
+		chunk, err := stream.Recv()
+		if err != nil {
+			if st, ok := status.FromError(err); ok {
+				http.Error(w, st.Message(), httpStatusFromGrpc(st.Code()))
+				return
+			}
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": chunk.GetFilename()}))
+		for {
+			if _, err := w.Write(chunk.GetData()); err != nil {
+				slog.Error("failed to write export", "error", err)
+				return
+			}
+			if chunk, err = stream.Recv(); err == io.EOF {
+				return
+			} else if err != nil {
+				// the status line is already sent; a truncated zip fails to open
+				slog.Error("export stream failed", "error", err)
+				return
+			}
+		}
+	})
+
 	// Wrap mux with CORS
 	handler := cors.AllowAll().Handler(mux)
 
 	// Server setup
-	serverAddr := fmt.Sprintf(":%d", cfg.AuthPort)
+	serverAddr := fmt.Sprintf(":%d", cfg.UserClientPort)
 	srv := &http.Server{Addr: serverAddr, Handler: handler}
+	// Shutdown does not track upgraded connections, so close them ourselves
+	srv.RegisterOnShutdown(live.Close)
 
 	go func() {
 		logger.Info("starting REST proxy", slog.String("listen", serverAddr))
@@ -207,7 +432,19 @@ func runClient(ctx context.Context, logger *slog.Logger) error {
 	<-ctx.Done()
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	return srv.Shutdown(shutdownCtx)
+	err = srv.Shutdown(shutdownCtx)
+	live.Wait()
+	return err
+}
+
+// outgoingContext forwards the caller's Authorization header to the gRPC backend,
+// which authenticates every non-public route.
+func outgoingContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+	}
+	return ctx
 }
 
 func httpStatusFromGrpc(code codes.Code) int {
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	"github.com/yaninyzwitty/chat/packages/shared/events"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// sseKeepAlive is how long a quiet stream goes before a comment is sent
+	// to keep proxies from timing it out
+	sseKeepAlive = 15 * time.Second
+	// sseRetry is the reconnect delay suggested to clients, in milliseconds
+	sseRetry = 3000
+	// sseBuffer is how many events a stream may fall behind the log before
+	// it is closed; the client reconnects and resumes from its last id
+	sseBuffer = 256
+)
+
+var errSSESlow = errors.New("event stream fell behind")
+
+// sseHandler serves GET /events: the caller's event log as Server-Sent
+// Events, for clients that only receive. Every event carries its sequence as
+// the event id, so a reconnecting client's Last-Event-ID resumes right after
+// it. Ephemeral events such as typing are not logged and not sent.
+type sseHandler struct {
+	log  *events.Log
+	live *liveStreams
+}
+
+func newSSEHandler(log *events.Log, live *liveStreams) *sseHandler {
+	return &sseHandler{log: log, live: live}
+}
+
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+	claims, err := authjwt.ValidateJWT(token)
+	if err != nil || claims.UserID == "" {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var last uint64
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		if last, err = strconv.ParseUint(id, 10, 64); err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if !h.live.track() {
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.live.done()
+
+	ctx := r.Context()
+	from, resumed, err := h.log.Resume(ctx, claims.UserID, last)
+	if err != nil {
+		slog.Error("failed to resume event stream", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+	if last != 0 && !resumed {
+		// events after last are gone; the client should reload what it shows
+		fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {}\n\n", from)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	// tokens are checked once, so the stream ends when this one expires
+	var expired <-chan time.Time
+	if claims.ExpiresAt != nil {
+		timer := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	// the log's followers share one reader that must never wait on a
+	// client, so events are queued here and written below
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+	queue := make(chan *realtimev1.Event, sseBuffer)
+	followed := make(chan error, 1)
+	go func() {
+		followed <- h.log.Follow(ctx, claims.UserID, from, func(ev *realtimev1.Event) {
+			select {
+			case queue <- ev:
+			default:
+				stop(errSSESlow)
+			}
+		})
+	}()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-h.live.Closing():
+			return
+		case <-expired:
+			fmt.Fprint(w, "event: expired\ndata: {}\n\n")
+			_ = rc.Flush()
+			return
+		case err := <-followed:
+			if cause := context.Cause(ctx); cause != nil {
+				err = cause
+			}
+			// a stream that fell behind just ends; the client resumes
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, errSSESlow) {
+				slog.Error("failed to read event stream", "error", err)
+			}
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case ev := <-queue:
+			data, err := protojson.Marshal(ev)
+			if err != nil {
+				slog.Error("failed to encode event", "error", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Sequence, sseEventName(ev), data)
+			keepAlive.Reset(sseKeepAlive)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func sseEventName(ev *realtimev1.Event) string {
+	switch ev.GetPayload().(type) {
+	case *realtimev1.Event_MessageCreated:
+		return "message_created"
+	case *realtimev1.Event_Membership:
+		return "membership"
+	case *realtimev1.Event_Typing:
+		return "typing"
+	case *realtimev1.Event_ReceiptUpdated:
+		return "receipt_updated"
+	case *realtimev1.Event_MessageEdited:
+		return "message_edited"
+	case *realtimev1.Event_MessageDeleted:
+		return "message_deleted"
+	case *realtimev1.Event_ReactionUpdated:
+		return "reaction_updated"
+	default:
+		return "event"
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	realtimev1 "github.com/yaninyzwitty/chat/gen/realtime/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// wsWriteTimeout bounds one write to the browser; a client that cannot
+	// take a frame in this long is dropped
+	wsWriteTimeout = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+	wsPingTimeout  = 10 * time.Second
+	// wsReadLimit caps a single frame from the browser
+	wsReadLimit = 64 << 10
+	// close reasons longer than this do not fit in a close frame
+	wsMaxReason = 123
+
+	// application close codes mirroring the HTTP statuses
+	wsCloseUnauthenticated websocket.StatusCode = 4401
+	wsClosePermission      websocket.StatusCode = 4403
+)
+
+// wsGateway bridges browser WebSockets to RealtimeService.Connect. Each text
+// frame from the browser is a ClientFrame in protobuf JSON form, and each
+// ServerFrame goes back the same way.
+//
+// Frames are read from the backend only as fast as the browser takes them,
+// so a slow browser stalls its gRPC stream; the realtime service then drops
+// the connection once its send buffer fills, and the socket is closed with
+// 1013 (try again later) so the client reconnects and resumes.
+type wsGateway struct {
+	client realtimev1.RealtimeServiceClient
+	live   *liveStreams
+}
+
+func newWSGateway(client realtimev1.RealtimeServiceClient, live *liveStreams) *wsGateway {
+	return &wsGateway{client: client, live: live}
+}
+
+// ServeHTTP authenticates the caller, opens their realtime stream and only
+// then upgrades, so a rejected token gets a plain HTTP error.
+func (g *wsGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, viaProtocol := wsToken(r)
+	if token == "" {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	stream, err := g.client.Connect(ctx)
+	if err == nil {
+		// the service sends headers once it accepts the call; without them
+		// the call already ended and Recv has the reason
+		var md metadata.MD
+		if md, err = stream.Header(); err == nil && md == nil {
+			_, err = stream.Recv()
+		}
+	}
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			http.Error(w, st.Message(), httpStatusFromGrpc(st.Code()))
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !g.live.track() {
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer g.live.done()
+
+	opts := &websocket.AcceptOptions{
+		// every call carries a bearer token rather than cookies, so
+		// cross-origin pages gain nothing, matching the CORS policy
+		InsecureSkipVerify: true,
+	}
+	if viaProtocol {
+		opts.Subprotocols = []string{"bearer"}
+	}
+	conn, err := websocket.Accept(w, r, opts)
+	if err != nil {
+		// Accept has already answered the request
+		slog.Warn("websocket upgrade failed", "error", err)
+		return
+	}
+	conn.SetReadLimit(wsReadLimit)
+
+	code, reason := g.bridge(ctx, conn, stream)
+	// cancelling ends the gRPC stream before the socket says goodbye
+	cancel()
+	if len(reason) > wsMaxReason {
+		reason = reason[:wsMaxReason]
+	}
+	if err := conn.Close(code, reason); err != nil && websocket.CloseStatus(err) == -1 {
+		slog.Debug("websocket close", "error", err)
+	}
+}
+
+// bridge pumps frames both ways until either side ends or the gateway
+// closes, returning how to close the socket.
+func (g *wsGateway) bridge(ctx context.Context, conn *websocket.Conn, stream realtimev1.RealtimeService_ConnectClient) (websocket.StatusCode, string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type closing struct {
+		code   websocket.StatusCode
+		reason string
+	}
+	// the first side to stop decides how the socket closes
+	done := make(chan closing, 1)
+	finish := func(code websocket.StatusCode, reason string) {
+		select {
+		case done <- closing{code, reason}:
+		default:
+		}
+		cancel()
+	}
+
+	// browser to backend
+	go func() {
+		for {
+			typ, data, err := conn.Read(ctx)
+			if err != nil {
+				finish(websocket.StatusNormalClosure, "")
+				return
+			}
+			if typ != websocket.MessageText {
+				finish(websocket.StatusUnsupportedData, "frames must be JSON text")
+				return
+			}
+			frame := &realtimev1.ClientFrame{}
+			if err := protojson.Unmarshal(data, frame); err != nil {
+				finish(websocket.StatusInvalidFramePayloadData, "invalid frame: "+err.Error())
+				return
+			}
+			// a failed send means the call ended; Recv reports why
+			if err := stream.Send(frame); err != nil {
+				return
+			}
+		}
+	}()
+
+	// backend to browser
+	go func() {
+		for {
+			frame, err := stream.Recv()
+			if err != nil {
+				finish(wsCloseFromGrpc(err))
+				return
+			}
+			data, err := protojson.Marshal(frame)
+			if err != nil {
+				finish(websocket.StatusInternalError, "failed to encode frame")
+				return
+			}
+			wctx, wcancel := context.WithTimeout(ctx, wsWriteTimeout)
+			err = conn.Write(wctx, websocket.MessageText, data)
+			wcancel()
+			if err != nil {
+				finish(websocket.StatusTryAgainLater, "connection too slow")
+				return
+			}
+		}
+	}()
+
+	// keepalive; pongs are read by the browser-to-backend pump
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pctx, pcancel := context.WithTimeout(ctx, wsPingTimeout)
+				err := conn.Ping(pctx)
+				pcancel()
+				if err != nil {
+					finish(websocket.StatusGoingAway, "ping timeout")
+					return
+				}
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-g.live.Closing():
+		finish(websocket.StatusGoingAway, "server shutting down")
+	}
+	select {
+	case c := <-done:
+		return c.code, c.reason
+	default:
+		return websocket.StatusGoingAway, ""
+	}
+}
+
+// wsToken returns the caller's bearer token from the Authorization header or,
+// since browsers cannot set headers on a WebSocket, from the subprotocols
+// offered as "bearer", "<token>". viaProtocol reports the latter, in which
+// case the handshake must select "bearer".
+func wsToken(r *http.Request) (token string, viaProtocol bool) {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "bearer") {
+		return token, false
+	}
+
+	var protocols []string
+	for _, v := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for p := range strings.SplitSeq(v, ",") {
+			protocols = append(protocols, strings.TrimSpace(p))
+		}
+	}
+	if len(protocols) == 2 && protocols[0] == "bearer" {
+		return protocols[1], true
+	}
+	return "", false
+}
+
+// wsCloseFromGrpc maps how the realtime call ended to a close frame.
+func wsCloseFromGrpc(err error) (websocket.StatusCode, string) {
+	if errors.Is(err, io.EOF) {
+		return websocket.StatusNormalClosure, ""
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return websocket.StatusInternalError, "internal server error"
+	}
+	switch st.Code() {
+	case codes.Unauthenticated:
+		return wsCloseUnauthenticated, st.Message()
+	case codes.PermissionDenied:
+		return wsClosePermission, st.Message()
+	case codes.InvalidArgument:
+		return websocket.StatusPolicyViolation, st.Message()
+	case codes.ResourceExhausted, codes.Unavailable:
+		return websocket.StatusTryAgainLater, st.Message()
+	case codes.Canceled:
+		return websocket.StatusGoingAway, ""
+	default:
+		return websocket.StatusInternalError, st.Message()
+	}
+}
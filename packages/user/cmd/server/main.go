@@ -19,6 +19,9 @@ import (
 	database "github.com/yaninyzwitty/chat/packages/db"
 	"github.com/yaninyzwitty/chat/packages/shared/config"
 	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/shared/pagetoken"
+	"github.com/yaninyzwitty/chat/packages/shared/redisclient"
+	"github.com/yaninyzwitty/chat/packages/user/avatar"
 	"github.com/yaninyzwitty/chat/packages/user/controller"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -71,6 +74,7 @@ func run(ctx context.Context) error {
 	// gRPC server setup
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(authjWT.AuthInterceptor()),
+		grpc.StreamInterceptor(authjWT.StreamAuthInterceptor()),
 	)
 
 	// ✅ Health check registration
@@ -92,8 +96,37 @@ func run(ctx context.Context) error {
 		return errors.New("ASTRA_DB_TOKEN environment variable is not set")
 	}
 
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		return errors.New("REDIS_URL environment variable is not set")
+	}
+
+	redisClient, err := redisclient.Connect(redisURL)
+	if err != nil {
+		return fmt.Errorf("failed to create redis client: %w", err)
+	}
+
+	pageTokenSecret := os.Getenv("PAGE_TOKEN_SECRET")
+	if pageTokenSecret == "" {
+		return errors.New("PAGE_TOKEN_SECRET environment variable is not set")
+	}
+
+	pages, err := pagetoken.New([]byte(pageTokenSecret), time.Duration(cfg.User.PageTokenTTL)*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to create page token codec: %w", err)
+	}
+
 	db := database.ConnectAstra(cfg, dbToken)
-	userController := controller.NewUserController(ctx, cfg, reg, dbToken, db)
+
+	if err := avatar.CheckSizes(cfg.Avatar.Sizes); err != nil {
+		return fmt.Errorf("invalid avatar sizes: %w", err)
+	}
+	blobs, err := avatar.NewLocalBlobStore(cfg.Avatar.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to open avatar store: %w", err)
+	}
+
+	userController := controller.NewUserController(ctx, cfg, reg, dbToken, db, blobs, redisClient, pages)
 	userv1.RegisterUserServiceServer(grpcServer, userController)
 
 	errorGroup, ctx := errgroup.WithContext(ctx)
@@ -117,6 +150,16 @@ func run(ctx context.Context) error {
 		return nil
 	})
 
+	// User cache invalidation listener
+	errorGroup.Go(func() error {
+		return userController.RunUserCache(ctx)
+	})
+
+	// Presence reaper goroutine
+	errorGroup.Go(func() error {
+		return userController.RunPresenceReaper(ctx)
+	})
+
 	// Shutdown goroutine
 	errorGroup.Go(func() error {
 		<-ctx.Done() // wait for signal
@@ -135,6 +178,10 @@ func run(ctx context.Context) error {
 			slog.Info("closed Cassandra session")
 		}
 
+		if err := redisClient.Close(); err != nil {
+			slog.Warn("failed to close redis client", "error", err)
+		}
+
 		return ctx.Err()
 	})
 
@@ -0,0 +1,201 @@
+// Command usertool bulk-imports users into chat.users and exports them.
+//
+//	usertool import [-concurrency 8] [-checkpoint users.csv.checkpoint] users.csv
+//	usertool export [-out users.jsonl]
+//
+// Imports accept CSV (with a header row) or JSONL carrying id, name,
+// alias_name, email, created_at and either password_hash (bcrypt) or
+// password. An interrupted import resumes from its checkpoint when rerun.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gocql/gocql"
+	"github.com/joho/godotenv"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	database "github.com/yaninyzwitty/chat/packages/db"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/user/bulk"
+	"github.com/yaninyzwitty/chat/packages/user/handler"
+)
+
+const usage = `usage:
+  usertool import [flags] FILE    import users from a .csv or .jsonl file
+  usertool export [flags]         export users to JSONL
+
+run "usertool import -h" or "usertool export -h" for flags`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		slog.Error("usertool failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+// dbFlags are shared by both subcommands.
+type dbFlags struct {
+	config *string
+	local  *bool
+}
+
+func addDBFlags(fs *flag.FlagSet) dbFlags {
+	return dbFlags{
+		config: fs.String("config", "config.yaml", "Path to config file"),
+		local:  fs.Bool("local", false, "Connect to the local Cassandra in the config instead of Astra"),
+	}
+}
+
+func (f dbFlags) connect() (*gocql.Session, error) {
+	cfg := &config.Config{}
+	if err := cfg.LoadConfig(*f.config); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *f.local {
+		return database.ConnectLocal(cfg.DatabaseConfig.Local_Host)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		slog.Warn("Failed to load .env")
+	}
+	dbToken := os.Getenv("ASTRA_DB_TOKEN")
+	if dbToken == "" {
+		return nil, errors.New("ASTRA_DB_TOKEN environment variable is not set")
+	}
+	return database.ConnectAstra(cfg, dbToken), nil
+}
+
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	db := addDBFlags(fs)
+	concurrency := fs.Int("concurrency", bulk.DefaultConcurrency, "Records written at once")
+	checkpointPath := fs.String("checkpoint", "", "Checkpoint file (default FILE.checkpoint)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("import needs exactly one input file")
+	}
+	path := fs.Arg(0)
+
+	format, err := bulk.FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	if *checkpointPath == "" {
+		*checkpointPath = path + ".checkpoint"
+	}
+	cp, err := bulk.LoadCheckpoint(*checkpointPath, path)
+	if err != nil {
+		return err
+	}
+	if line := cp.Line(); line > 0 {
+		slog.Info("resuming import", slog.Int("after_record", line))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open input: %w", err)
+	}
+	defer f.Close()
+
+	reader, err := bulk.NewReader(f, format)
+	if err != nil {
+		return err
+	}
+
+	session, err := db.connect()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stats, err := bulk.Import(ctx, reader, handler.NewUserHandler(session), cp, bulk.Options{
+		Concurrency: *concurrency,
+		Skipped: func(line int, reason error) {
+			slog.Warn("skipped record", slog.Int("record", line), slog.String("reason", reason.Error()))
+		},
+	})
+	slog.Info("import finished",
+		slog.Int("imported", stats.Imported),
+		slog.Int("existing", stats.Existing),
+		slog.Int("duplicate", stats.Duplicate),
+		slog.Int("invalid", stats.Invalid),
+		slog.Int("resumed", stats.Resumed),
+	)
+	if err != nil {
+		return fmt.Errorf("import stopped, rerun to resume from record %d: %w", cp.Line()+1, err)
+	}
+
+	return cp.Remove()
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	db := addDBFlags(fs)
+	out := fs.String("out", "", "Output file (default stdout)")
+	pageSize := fs.Int("page-size", 500, "Rows fetched per page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create output: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	session, err := db.connect()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	exporter := bulk.NewExporter(w)
+	var n int
+	err = handler.NewUserHandler(session).ExportUsers(ctx, *pageSize, func(u *userv1.User) error {
+		n++
+		return exporter.Write(u)
+	})
+	if err != nil {
+		return err
+	}
+	if err := exporter.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	slog.Info("export finished", slog.Int("users", n))
+	return nil
+}
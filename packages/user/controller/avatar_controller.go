@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/avatar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- UPLOAD AVATAR ---
+func (c *UserController) UploadAvatar(stream grpc.ClientStreamingServer[userv1.UploadAvatarRequest, userv1.UploadAvatarResponse]) error {
+	start := time.Now()
+	const op = "upload_avatar"
+	ctx := stream.Context()
+
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	maxBytes := c.Config.Avatar.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = avatar.DefaultMaxBytes
+	}
+
+	// the first message must describe the upload
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "avatar metadata is missing")
+	}
+	if err != nil {
+		return recvError(err, "avatar metadata")
+	}
+	meta := first.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry avatar metadata")
+	}
+	if !slices.Contains(avatar.AllowedContentTypes, meta.GetContentType()) {
+		return status.Errorf(codes.InvalidArgument, "content type %q is not allowed; use one of %s",
+			meta.GetContentType(), strings.Join(avatar.AllowedContentTypes, ", "))
+	}
+	if meta.GetSize() > uint64(maxBytes) {
+		return status.Errorf(codes.InvalidArgument, "avatar exceeds the %d byte limit", maxBytes)
+	}
+
+	var buf bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return recvError(err, "avatar chunk")
+		}
+		chunk := req.GetChunk()
+		if chunk == nil {
+			return status.Error(codes.InvalidArgument, "metadata may only be sent once")
+		}
+		if int64(buf.Len()+len(chunk)) > maxBytes {
+			return status.Errorf(codes.InvalidArgument, "avatar exceeds the %d byte limit", maxBytes)
+		}
+		buf.Write(chunk)
+	}
+	if buf.Len() == 0 {
+		return status.Error(codes.InvalidArgument, "avatar is empty")
+	}
+
+	sizes := c.Config.Avatar.Sizes
+	if len(sizes) == 0 {
+		sizes = avatar.DefaultSizes
+	}
+
+	thumbs, err := avatar.Process(buf.Bytes(), sizes)
+	if err != nil {
+		c.observeError(op, "image")
+		if errors.Is(err, avatar.ErrUnsupportedType) || errors.Is(err, avatar.ErrInvalidImage) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return status.Errorf(codes.Internal, "failed to process avatar: %v", err)
+	}
+
+	version := gocql.TimeUUID().String()
+	keys, err := avatar.Save(ctx, c.blobs, caller, version, thumbs)
+	if err != nil {
+		c.observeError(op, "blob")
+		return status.Errorf(codes.Internal, "failed to store avatar: %v", err)
+	}
+
+	baseURL := strings.TrimRight(c.Config.Avatar.PublicBaseURL, "/")
+	resp := &userv1.UploadAvatarResponse{}
+	for i, key := range keys {
+		resp.Thumbnails = append(resp.Thumbnails, &userv1.AvatarThumbnail{
+			Size: uint32(thumbs[i].Size),
+			Url:  baseURL + "/" + key,
+		})
+	}
+
+	// the largest rendition becomes the profile's avatar
+	largest := resp.Thumbnails[0]
+	for _, t := range resp.Thumbnails {
+		if t.Size > largest.Size {
+			largest = t
+		}
+	}
+
+	// the upload replaces the version the profile points at now
+	prev, err := c.h.GetUser(ctx, caller)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		c.removeAvatar(ctx, caller, version)
+		return err
+	}
+	user, err := c.h.UpdateProfile(ctx, caller, &userv1.User{AvatarUrl: largest.Url}, []string{"avatar_url"})
+	if err != nil {
+		c.observeError(op, "cassandra")
+		c.removeAvatar(ctx, caller, version)
+		return err
+	}
+	resp.User = user
+
+	if key, ok := strings.CutPrefix(prev.GetAvatarUrl(), baseURL+"/"); ok {
+		if old, ok := avatar.VersionOf(caller, key); ok && old != version {
+			c.removeAvatar(ctx, caller, old)
+		}
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return stream.SendAndClose(resp)
+}
+
+// recvError reports a failed stream receive. Errors that already carry a
+// status, such as a cancelled or expired call, keep it; anything else is
+// the transport failing underneath.
+func recvError(err error, what string) error {
+	if s, ok := status.FromError(err); ok {
+		return s.Err()
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Errorf(codes.Unavailable, "failed to receive %s: %v", what, err)
+}
+
+// removeAvatar deletes one uploaded version, logging rather than failing the
+// call; an orphaned version costs only storage.
+func (c *UserController) removeAvatar(ctx context.Context, userID, version string) {
+	if err := avatar.Remove(ctx, c.blobs, userID, version); err != nil {
+		slog.Warn("failed to delete avatar version", "user", userID, "version", version, "error", err)
+	}
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	"github.com/yaninyzwitty/chat/packages/shared/blocklist"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- BLOCK USER ---
+func (c *UserController) BlockUser(ctx context.Context, req *userv1.BlockUserRequest) (*userv1.BlockUserResponse, error) {
+	start := time.Now()
+	const op = "block_user"
+
+	caller, target, err := c.callerAndTarget(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	// the blocked user must exist
+	if _, err := c.h.GetUser(ctx, target.String()); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	if err := c.h.BlockUser(ctx, caller, target); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.BlockUserResponse{}, nil
+}
+
+// --- UNBLOCK USER ---
+func (c *UserController) UnblockUser(ctx context.Context, req *userv1.UnblockUserRequest) (*userv1.UnblockUserResponse, error) {
+	start := time.Now()
+	const op = "unblock_user"
+
+	caller, target, err := c.callerAndTarget(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.h.UnblockUser(ctx, caller, target); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.UnblockUserResponse{}, nil
+}
+
+// --- LIST BLOCKED USERS ---
+func (c *UserController) ListBlockedUsers(ctx context.Context, req *userv1.ListBlockedUsersRequest) (*userv1.ListBlockedUsersResponse, error) {
+	start := time.Now()
+	const op = "list_blocked_users"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, pageState, err := c.decodePage(ctx, op, req.GetPageLimit(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, next, err := c.h.ListBlockedUsers(ctx, caller, pageSize, pageState)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.ListBlockedUsersResponse{BlockedUsers: blocked, PageToken: c.encodePage(ctx, op, pageSize, next)}, nil
+}
+
+// --- MUTE USER ---
+func (c *UserController) MuteUser(ctx context.Context, req *userv1.MuteUserRequest) (*userv1.MuteUserResponse, error) {
+	start := time.Now()
+	const op = "mute_user"
+
+	caller, target, err := c.callerAndTarget(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	// zero ttl keeps the mute until it is lifted explicitly
+	var ttl time.Duration
+	if req.ExpiresAt != nil {
+		if err := req.ExpiresAt.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expires_at: %v", err)
+		}
+		ttl = time.Until(req.ExpiresAt.AsTime())
+		if ttl < time.Second {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+	}
+
+	if err := c.h.MuteUser(ctx, caller, target, ttl); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.MuteUserResponse{}, nil
+}
+
+// --- UNMUTE USER ---
+func (c *UserController) UnmuteUser(ctx context.Context, req *userv1.UnmuteUserRequest) (*userv1.UnmuteUserResponse, error) {
+	start := time.Now()
+	const op = "unmute_user"
+
+	caller, target, err := c.callerAndTarget(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.h.UnmuteUser(ctx, caller, target); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.UnmuteUserResponse{}, nil
+}
+
+// callerAndTarget resolves the caller and the user_id they are acting on,
+// rejecting attempts to act on themselves.
+func (c *UserController) callerAndTarget(ctx context.Context, userID string) (gocql.UUID, gocql.UUID, error) {
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return gocql.UUID{}, gocql.UUID{}, err
+	}
+	target, err := parseUUID("user_id", userID)
+	if err != nil {
+		return gocql.UUID{}, gocql.UUID{}, err
+	}
+	if target == caller {
+		return gocql.UUID{}, gocql.UUID{}, status.Error(codes.InvalidArgument, "cannot target yourself")
+	}
+	return caller, target, nil
+}
+
+// blockedFor returns which of users are blocked in either direction relative
+// to the caller. Anonymous callers see no blocks.
+func (c *UserController) blockedFor(ctx context.Context, users []*userv1.User) (map[gocql.UUID]bool, error) {
+	claims, ok := authjwt.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	viewer, err := gocql.ParseUUID(claims.UserID)
+	if err != nil {
+		return nil, nil
+	}
+
+	ids := make([]gocql.UUID, 0, len(users))
+	for _, u := range users {
+		if id, err := gocql.ParseUUID(u.GetId()); err == nil && id != viewer {
+			ids = append(ids, id)
+		}
+	}
+
+	blocked, err := blocklist.Blocked(ctx, c.h.Db, viewer, ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check blocks: %v", err)
+	}
+	return blocked, nil
+}
+
+// redactBlocked strips everything but the id from users that are blocked
+// relative to the caller.
+func (c *UserController) redactBlocked(ctx context.Context, users []*userv1.User) ([]*userv1.User, error) {
+	blocked, err := c.blockedFor(ctx, users)
+	if err != nil || len(blocked) == 0 {
+		return users, err
+	}
+
+	out := make([]*userv1.User, len(users))
+	for i, u := range users {
+		out[i] = u
+		if id, _ := gocql.ParseUUID(u.GetId()); blocked[id] {
+			out[i] = &userv1.User{Id: u.GetId()}
+		}
+	}
+	return out, nil
+}
+
+// filterBlocked drops users that are blocked relative to the caller.
+func (c *UserController) filterBlocked(ctx context.Context, users []*userv1.User) ([]*userv1.User, error) {
+	blocked, err := c.blockedFor(ctx, users)
+	if err != nil || len(blocked) == 0 {
+		return users, err
+	}
+
+	out := users[:0]
+	for _, u := range users {
+		if id, _ := gocql.ParseUUID(u.GetId()); !blocked[id] {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var changeKinds = map[changelog.Kind]userv1.UserChangeKind{
+	changelog.KindCreated:     userv1.UserChangeKind_USER_CHANGE_KIND_CREATED,
+	changelog.KindUpdated:     userv1.UserChangeKind_USER_CHANGE_KIND_UPDATED,
+	changelog.KindSuspended:   userv1.UserChangeKind_USER_CHANGE_KIND_SUSPENDED,
+	changelog.KindUnsuspended: userv1.UserChangeKind_USER_CHANGE_KIND_UNSUSPENDED,
+}
+
+// --- WATCH USERS ---
+// WatchUsers streams user changes after the request cursor, then tails new
+// ones. It exposes every user's full record, so it is limited to admins,
+// which includes the services consuming it.
+func (c *UserController) WatchUsers(req *userv1.WatchUsersRequest, stream grpc.ServerStreamingServer[userv1.WatchUsersResponse]) error {
+	const op = "watch_users"
+	ctx := stream.Context()
+
+	if _, err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	var cursor gocql.UUID
+	if req.GetCursor() != "" {
+		var err error
+		if cursor, err = changelog.DecodeCursor(req.GetCursor()); err != nil {
+			return err
+		}
+	}
+
+	var only map[gocql.UUID]bool
+	if len(req.GetUserIds()) > 0 {
+		ids := uniqueIDs(req.GetUserIds())
+		if maxIDs := c.batchMaxIDs(); len(ids) > maxIDs {
+			return status.Errorf(codes.InvalidArgument, "at most %d user ids may be watched at once", maxIDs)
+		}
+		only = make(map[gocql.UUID]bool, len(ids))
+		for _, id := range ids {
+			userID, err := parseUUID("user_ids", id)
+			if err != nil {
+				return err
+			}
+			only[userID] = true
+		}
+	}
+
+	err := c.changes.Watch(ctx, cursor, func(e changelog.Entry) error {
+		if only != nil && !only[e.UserID] {
+			return nil
+		}
+
+		user, err := c.h.GetUser(ctx, e.UserID.String())
+		if status.Code(err) == codes.NotFound {
+			// logged ahead of a mutation that then failed
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return stream.Send(&userv1.WatchUsersResponse{Change: &userv1.UserChange{
+			Kind:          changeKinds[e.Kind],
+			UserId:        e.UserID.String(),
+			ChangedFields: e.Fields,
+			ChangedAt:     timestamppb.New(e.ID.Time()),
+			User:          user,
+			Cursor:        changelog.EncodeCursor(e.ID),
+		}})
+	})
+	if err != nil && status.Code(err) == codes.Internal {
+		c.observeError(op, "cassandra")
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/blocklist"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxContactRequestMessageLength = 200
+
+// --- SEND CONTACT REQUEST ---
+func (c *UserController) SendContactRequest(ctx context.Context, req *userv1.SendContactRequestRequest) (*userv1.SendContactRequestResponse, error) {
+	start := time.Now()
+	const op = "send_contact_request"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if target == caller {
+		return nil, status.Error(codes.InvalidArgument, "cannot send a contact request to yourself")
+	}
+	if n := utf8.RuneCountInString(req.GetMessage()); n > maxContactRequestMessageLength {
+		return nil, status.Errorf(codes.InvalidArgument, "message must be at most %d characters, got %d", maxContactRequestMessageLength, n)
+	}
+
+	// the recipient must exist
+	if _, err := c.h.GetUser(ctx, target.String()); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	blocked, err := blocklist.IsBlocked(ctx, c.h.Db, caller, target)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, status.Errorf(codes.Internal, "failed to check blocks: %v", err)
+	}
+	if blocked {
+		return nil, status.Error(codes.PermissionDenied, "cannot send a contact request to this user")
+	}
+
+	request, err := c.h.SendContactRequest(ctx, caller, target, req.GetMessage())
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.SendContactRequestResponse{Request: request}, nil
+}
+
+// --- ACCEPT CONTACT REQUEST ---
+func (c *UserController) AcceptContactRequest(ctx context.Context, req *userv1.AcceptContactRequestRequest) (*userv1.AcceptContactRequestResponse, error) {
+	start := time.Now()
+	const op = "accept_contact_request"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requester, err := parseUUID("requester_id", req.GetRequesterId())
+	if err != nil {
+		return nil, err
+	}
+
+	// only the recipient's own partition is consulted, so a caller can only
+	// accept requests addressed to them
+	contact, err := c.h.AcceptContactRequest(ctx, caller, requester)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.AcceptContactRequestResponse{Contact: contact}, nil
+}
+
+// --- DECLINE CONTACT REQUEST ---
+func (c *UserController) DeclineContactRequest(ctx context.Context, req *userv1.DeclineContactRequestRequest) (*userv1.DeclineContactRequestResponse, error) {
+	start := time.Now()
+	const op = "decline_contact_request"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requester, err := parseUUID("requester_id", req.GetRequesterId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.h.DeclineContactRequest(ctx, caller, requester); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.DeclineContactRequestResponse{}, nil
+}
+
+// --- REMOVE CONTACT ---
+func (c *UserController) RemoveContact(ctx context.Context, req *userv1.RemoveContactRequest) (*userv1.RemoveContactResponse, error) {
+	start := time.Now()
+	const op = "remove_contact"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contactID, err := parseUUID("contact_id", req.GetContactId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.h.RemoveContact(ctx, caller, contactID); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.RemoveContactResponse{}, nil
+}
+
+// --- LIST CONTACTS ---
+func (c *UserController) ListContacts(ctx context.Context, req *userv1.ListContactsRequest) (*userv1.ListContactsResponse, error) {
+	start := time.Now()
+	const op = "list_contacts"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, pageState, err := c.decodePage(ctx, op, req.GetPageLimit(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, next, err := c.h.ListContacts(ctx, caller, pageSize, pageState)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.ListContactsResponse{Contacts: contacts, PageToken: c.encodePage(ctx, op, pageSize, next)}, nil
+}
+
+// --- LIST PENDING REQUESTS ---
+func (c *UserController) ListPendingRequests(ctx context.Context, req *userv1.ListPendingRequestsRequest) (*userv1.ListPendingRequestsResponse, error) {
+	start := time.Now()
+	const op = "list_pending_requests"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// incoming and outgoing requests page through different tables
+	scope := op + ":incoming"
+	if req.GetOutgoing() {
+		scope = op + ":outgoing"
+	}
+	pageSize, pageState, err := c.decodePage(ctx, scope, req.GetPageLimit(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	requests, next, err := c.h.ListPendingRequests(ctx, caller, req.GetOutgoing(), pageSize, pageState)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.ListPendingRequestsResponse{Requests: requests, PageToken: c.encodePage(ctx, scope, pageSize, next)}, nil
+}
+
+// parseUUID validates a UUID request field, naming the field on failure.
+func parseUUID(field, value string) (gocql.UUID, error) {
+	if value == "" {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	id, err := gocql.ParseUUID(value)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
+	"github.com/yaninyzwitty/chat/packages/user/export"
+	"github.com/yaninyzwitty/chat/packages/user/handler"
+	"github.com/yaninyzwitty/chat/packages/user/settings"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- EXPORT MY DATA ---
+func (c *UserController) ExportMyData(req *userv1.ExportMyDataRequest, stream grpc.ServerStreamingServer[userv1.ExportDataChunk]) error {
+	caller, err := callerUUID(stream.Context())
+	if err != nil {
+		return err
+	}
+	return c.exportData(stream, "export_my_data", caller)
+}
+
+// --- EXPORT USER DATA ---
+func (c *UserController) ExportUserData(req *userv1.ExportUserDataRequest, stream grpc.ServerStreamingServer[userv1.ExportDataChunk]) error {
+	if _, err := requireAdmin(stream.Context()); err != nil {
+		return err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return err
+	}
+	return c.exportData(stream, "export_user_data", target)
+}
+
+// exportData streams a zip of everything held about userID.
+func (c *UserController) exportData(stream grpc.ServerStreamingServer[userv1.ExportDataChunk], op string, userID gocql.UUID) error {
+	start := time.Now()
+	ctx := stream.Context()
+
+	// resolve the profile before streaming so a missing user is a clean error
+	profile, err := c.h.ExportUserRow(ctx, userID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return err
+	}
+
+	filename := fmt.Sprintf("chat-export-%s-%s.zip", userID, start.UTC().Format("20060102"))
+	first := true
+	w := &export.ChunkWriter{Send: func(data []byte) error {
+		chunk := &userv1.ExportDataChunk{Data: data}
+		if first {
+			chunk.Filename = filename
+			first = false
+		}
+		return stream.Send(chunk)
+	}}
+
+	archive := export.NewArchive(w)
+	if err := c.writeExport(ctx, archive, userID, profile); err != nil {
+		c.observeError(op, "cassandra")
+		return err
+	}
+	if err := archive.Close(userID.String()); err != nil {
+		return status.Errorf(codes.Internal, "failed to finish export: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return nil
+}
+
+func (c *UserController) writeExport(ctx context.Context, archive *export.Archive, userID gocql.UUID, profile map[string]any) error {
+	id := userID.String()
+
+	if err := archive.AddJSON("profile.json", profile); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	s, err := c.h.GetSettings(ctx, userID, settings.Defaults(c.Config.Settings))
+	if err != nil {
+		return err
+	}
+	if err := archive.AddJSON("settings.json", s); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	for _, table := range handler.PersonalTables {
+		rows, err := c.h.ExportTableRows(ctx, table, userID)
+		if err != nil {
+			return err
+		}
+		if err := archive.AddJSON("tables/"+table+".json", rows); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	convIDs, err := c.h.ExportConversationIDs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// only conversations the user is still in; messages left behind in
+	// others are not found
+	messages := []map[string]any{}
+	receipts := []map[string]any{}
+	for _, convID := range convIDs {
+		rows, err := c.h.ExportMessages(ctx, convID, userID)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, rows...)
+
+		rows, err = c.h.ExportReceipts(ctx, convID, userID)
+		if err != nil {
+			return err
+		}
+		receipts = append(receipts, rows...)
+	}
+	if err := archive.AddJSON("tables/messages.json", messages); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := archive.AddJSON("tables/message_receipts.json", receipts); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	changes, err := c.exportChanges(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := archive.AddJSON("tables/user_changes.json", changes); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	presences, err := c.presence.Get(ctx, []string{id})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read presence: %v", err)
+	}
+	if err := archive.AddJSON("presence.json", presences[0]); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	// the token itself is a credential, so only its existence is exported
+	expiresAt, active, err := c.refreshTokens.RefreshTokenExpiry(ctx, id)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read sessions: %v", err)
+	}
+	sessions := []map[string]any{}
+	if active {
+		sessions = append(sessions, map[string]any{"type": "refresh_token", "expires_at": expiresAt})
+	}
+	if err := archive.AddJSON("sessions.json", sessions); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return c.exportAvatar(ctx, archive, profile)
+}
+
+// exportChanges returns the change log entries about userID that are still
+// kept. The log is partitioned by time rather than user, so this reads every
+// bucket in the retention window.
+func (c *UserController) exportChanges(ctx context.Context, userID gocql.UUID) ([]map[string]any, error) {
+	changes := []map[string]any{}
+	now := time.Now().UTC()
+	for b := now.Add(-changelog.Retention).Truncate(changelog.BucketWidth); !b.After(now); b = b.Add(changelog.BucketWidth) {
+		err := c.h.ScanChanges(ctx, changelog.Bucket(b), gocql.MinTimeUUID(b), func(e changelog.Entry) error {
+			if e.UserID == userID {
+				changes = append(changes, map[string]any{
+					"change_id": e.ID,
+					"kind":      e.Kind,
+					"fields":    e.Fields,
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// exportAvatar adds the current avatar image, if it lives in our blob store.
+func (c *UserController) exportAvatar(ctx context.Context, archive *export.Archive, profile map[string]any) error {
+	avatarURL, _ := profile["avatar_url"].(string)
+	prefix := strings.TrimRight(c.Config.Avatar.PublicBaseURL, "/") + "/"
+	key, ok := strings.CutPrefix(avatarURL, prefix)
+	if avatarURL == "" || !ok {
+		return nil
+	}
+
+	rc, err := c.blobs.Get(ctx, key)
+	if err != nil {
+		// a dangling URL is not worth failing the whole export over
+		return nil
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read avatar: %v", err)
+	}
+	if err := archive.AddFile("avatar/"+path.Base(key), data); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
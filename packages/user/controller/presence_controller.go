@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/presence"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- SET PRESENCE ---
+func (c *UserController) SetPresence(ctx context.Context, req *userv1.SetPresenceRequest) (*userv1.SetPresenceResponse, error) {
+	start := time.Now()
+	const op = "set_presence"
+
+	caller, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GetStatus() {
+	case userv1.PresenceStatus_PRESENCE_STATUS_ONLINE, userv1.PresenceStatus_PRESENCE_STATUS_AWAY:
+		p, changed, err := c.presence.Heartbeat(ctx, caller, req.GetStatus())
+		if err != nil {
+			c.observeError(op, "redis")
+			return nil, status.Errorf(codes.Internal, "failed to set presence: %v", err)
+		}
+		if changed {
+			c.publishPresence(ctx, p)
+		}
+		c.observeDuration(op, "redis", start)
+		return &userv1.SetPresenceResponse{Presence: p}, nil
+
+	case userv1.PresenceStatus_PRESENCE_STATUS_OFFLINE:
+		p, err := c.goOffline(ctx, caller, time.Now())
+		if err != nil {
+			c.observeError(op, "cassandra")
+			return nil, err
+		}
+		c.observeDuration(op, "cassandra", start)
+		return &userv1.SetPresenceResponse{Presence: p}, nil
+
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be online, away or offline")
+	}
+}
+
+// --- GET PRESENCE ---
+func (c *UserController) GetPresence(ctx context.Context, req *userv1.GetPresenceRequest) (*userv1.GetPresenceResponse, error) {
+	start := time.Now()
+	const op = "get_presence"
+
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+
+	presences, err := c.visiblePresences(ctx, []string{req.GetUserId()})
+	if err != nil {
+		c.observeError(op, "redis")
+		return nil, err
+	}
+
+	c.observeDuration(op, "redis", start)
+	return &userv1.GetPresenceResponse{Presence: presences[0]}, nil
+}
+
+// --- BATCH GET PRESENCE ---
+func (c *UserController) BatchGetPresence(ctx context.Context, req *userv1.BatchGetPresenceRequest) (*userv1.BatchGetPresenceResponse, error) {
+	start := time.Now()
+	const op = "batch_get_presence"
+
+	ids, err := c.presenceIDs(req.GetUserIds())
+	if err != nil {
+		return nil, err
+	}
+
+	presences, err := c.visiblePresences(ctx, ids)
+	if err != nil {
+		c.observeError(op, "redis")
+		return nil, err
+	}
+
+	c.observeDuration(op, "redis", start)
+	return &userv1.BatchGetPresenceResponse{Presences: presences}, nil
+}
+
+// --- WATCH PRESENCE ---
+func (c *UserController) WatchPresence(req *userv1.WatchPresenceRequest, stream grpc.ServerStreamingServer[userv1.WatchPresenceResponse]) error {
+	const op = "watch_presence"
+	ctx := stream.Context()
+
+	ids, err := c.presenceIDs(req.GetUserIds())
+	if err != nil {
+		return err
+	}
+
+	// privacy is resolved once per stream; later changes apply on reconnect
+	privacy, err := c.privacyFor(ctx, ids)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return err
+	}
+
+	// subscribe before taking the snapshot so no change falls in between
+	sub, err := c.presence.Subscribe(ctx, ids)
+	if err != nil {
+		c.observeError(op, "redis")
+		return status.Errorf(codes.Internal, "failed to subscribe to presence: %v", err)
+	}
+	defer func() {
+		if cerr := sub.Close(); cerr != nil {
+			slog.Warn("failed to close presence subscription", "error", cerr)
+		}
+	}()
+
+	snapshot, err := c.presences(ctx, ids)
+	if err != nil {
+		c.observeError(op, "redis")
+		return err
+	}
+	hideLastSeen(snapshot, privacy)
+	for _, p := range snapshot {
+		if err := stream.Send(&userv1.WatchPresenceResponse{Presence: p}); err != nil {
+			return err
+		}
+	}
+
+	msgs := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				return status.Error(codes.Unavailable, "presence subscription closed")
+			}
+			p, err := presence.Decode(msg)
+			if err != nil {
+				slog.Warn("dropping malformed presence event", "error", err)
+				continue
+			}
+			hideLastSeen([]*userv1.Presence{p}, privacy)
+			if err := stream.Send(&userv1.WatchPresenceResponse{Presence: p}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RunPresenceReaper marks users offline once their heartbeats lapse,
+// persisting last-seen and notifying watchers, until ctx is cancelled.
+func (c *UserController) RunPresenceReaper(ctx context.Context) error {
+	interval := max(c.presence.TTL/2, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			expired, err := c.presence.ClaimExpired(ctx, now)
+			if err != nil {
+				slog.Warn("failed to claim expired presence", "error", err)
+			}
+			for _, e := range expired {
+				if err := c.h.SetLastSeen(ctx, e.UserID, e.LastSeen); err != nil {
+					slog.Warn("failed to persist last seen", "user_id", e.UserID, "error", err)
+				}
+				c.publishPresence(ctx, offlinePresence(e.UserID, e.LastSeen))
+			}
+		}
+	}
+}
+
+// goOffline clears userID's presence and, if it was online, persists at as
+// its last-seen time.
+func (c *UserController) goOffline(ctx context.Context, userID string, at time.Time) (*userv1.Presence, error) {
+	wasOnline, err := c.presence.Offline(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to clear presence: %v", err)
+	}
+
+	p := offlinePresence(userID, at)
+	if !wasOnline {
+		// already offline; report the last-seen already on record
+		presences, err := c.presences(ctx, []string{userID})
+		if err != nil {
+			return nil, err
+		}
+		return presences[0], nil
+	}
+
+	if err := c.h.SetLastSeen(ctx, userID, at); err != nil {
+		return nil, err
+	}
+	c.publishPresence(ctx, p)
+	return p, nil
+}
+
+// presences resolves the live status of ids, filling last-seen for offline
+// users from Cassandra.
+func (c *UserController) presences(ctx context.Context, ids []string) ([]*userv1.Presence, error) {
+	for _, id := range ids {
+		if _, err := gocql.ParseUUID(id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid UUID %q: %v", id, err)
+		}
+	}
+
+	out, err := c.presence.Get(ctx, ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read presence: %v", err)
+	}
+
+	var offline []string
+	for _, p := range out {
+		if p.Status == userv1.PresenceStatus_PRESENCE_STATUS_OFFLINE {
+			offline = append(offline, p.UserId)
+		}
+	}
+	if len(offline) == 0 {
+		return out, nil
+	}
+
+	seen, err := c.h.LastSeen(ctx, offline, c.batchConcurrency())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range out {
+		if at, ok := seen[p.UserId]; ok {
+			p.LastSeenAt = timestamppb.New(at)
+		}
+	}
+	return out, nil
+}
+
+// visiblePresences is presences with last-seen withheld from users who hide it.
+func (c *UserController) visiblePresences(ctx context.Context, ids []string) ([]*userv1.Presence, error) {
+	presences, err := c.presences(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	privacy, err := c.privacyFor(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	hideLastSeen(presences, privacy)
+	return presences, nil
+}
+
+// presenceIDs de-duplicates ids and enforces the batch size limit.
+func (c *UserController) presenceIDs(ids []string) ([]string, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one user id is required")
+	}
+	if maxIDs := c.batchMaxIDs(); len(ids) > maxIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d user ids may be requested at once", maxIDs)
+	}
+	return ids, nil
+}
+
+func (c *UserController) publishPresence(ctx context.Context, p *userv1.Presence) {
+	if err := c.presence.Publish(ctx, p); err != nil {
+		slog.Warn("failed to publish presence", "user_id", p.UserId, "error", err)
+	}
+}
+
+func offlinePresence(userID string, lastSeen time.Time) *userv1.Presence {
+	return &userv1.Presence{
+		UserId:     userID,
+		Status:     userv1.PresenceStatus_PRESENCE_STATUS_OFFLINE,
+		LastSeenAt: timestamppb.New(lastSeen),
+		UpdatedAt:  timestamppb.Now(),
+	}
+}
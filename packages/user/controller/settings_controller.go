@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	"github.com/yaninyzwitty/chat/packages/user/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- GET SETTINGS ---
+func (c *UserController) GetSettings(ctx context.Context, req *userv1.GetSettingsRequest) (*userv1.GetSettingsResponse, error) {
+	start := time.Now()
+	const op = "get_settings"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := c.h.GetSettings(ctx, caller, settings.Defaults(c.Config.Settings))
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.GetSettingsResponse{Settings: s}, nil
+}
+
+// --- UPDATE SETTINGS ---
+func (c *UserController) UpdateSettings(ctx context.Context, req *userv1.UpdateSettingsRequest) (*userv1.UpdateSettingsResponse, error) {
+	start := time.Now()
+	const op = "update_settings"
+
+	caller, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetSettings() == nil {
+		return nil, status.Error(codes.InvalidArgument, "settings are required")
+	}
+
+	paths, err := settings.Paths(req.GetUpdateMask(), req.GetSettings())
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.Validate(req.GetSettings(), paths); err != nil {
+		return nil, err
+	}
+
+	defaults := settings.Defaults(c.Config.Settings)
+	current, err := c.h.GetSettings(ctx, caller, defaults)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if n, maxPrefs := settings.PreferenceCount(current.GetPreferences(), req.GetSettings(), paths), c.maxPreferences(); n > maxPrefs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d preferences may be stored, update would leave %d", maxPrefs, n)
+	}
+
+	if err := c.h.UpdateSettings(ctx, caller, req.GetSettings(), paths); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	s, err := c.h.GetSettings(ctx, caller, defaults)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.UpdateSettingsResponse{Settings: s}, nil
+}
+
+// maxPreferences is the most client-defined preferences a user may store.
+func (c *UserController) maxPreferences() int {
+	if n := c.Config.Settings.MaxPreferences; n > 0 {
+		return n
+	}
+	return settings.DefaultMaxPreferences
+}
+
+// privacyFor returns the privacy settings of ids other than the caller's
+// own, which the caller always sees in full.
+func (c *UserController) privacyFor(ctx context.Context, ids []string) (map[string]*userv1.PrivacySettings, error) {
+	var viewer string
+	if claims, ok := authjwt.ClaimsFromContext(ctx); ok {
+		viewer = claims.UserID
+	}
+
+	uuids := make([]gocql.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == viewer {
+			continue
+		}
+		if uuid, err := gocql.ParseUUID(id); err == nil {
+			uuids = append(uuids, uuid)
+		}
+	}
+
+	privacy, err := c.h.PrivacySettings(ctx, uuids, settings.Defaults(c.Config.Settings).GetPrivacy())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*userv1.PrivacySettings, len(privacy))
+	for id, p := range privacy {
+		out[id.String()] = p
+	}
+	return out, nil
+}
+
+// applyPrivacy hides the email of users who asked for it from everyone but
+// themselves.
+func (c *UserController) applyPrivacy(ctx context.Context, users []*userv1.User) error {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.GetId()
+	}
+
+	privacy, err := c.privacyFor(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if privacy[u.GetId()].GetHideEmail() {
+			u.Email = ""
+		}
+	}
+	return nil
+}
+
+// hideLastSeen drops last-seen from presences of users who asked for it.
+func hideLastSeen(presences []*userv1.Presence, privacy map[string]*userv1.PrivacySettings) {
+	for _, p := range presences {
+		if privacy[p.GetUserId()].GetHideLastSeen() {
+			p.LastSeenAt = nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	adminRole                 = "admin"
+	maxSuspensionReasonLength = 500
+)
+
+// --- SUSPEND USER ---
+func (c *UserController) SuspendUser(ctx context.Context, req *userv1.SuspendUserRequest) (*userv1.SuspendUserResponse, error) {
+	start := time.Now()
+	const op = "suspend_user"
+
+	admin, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if target == admin {
+		return nil, status.Error(codes.InvalidArgument, "cannot suspend yourself")
+	}
+
+	reason := req.GetReason()
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+	if n := utf8.RuneCountInString(reason); n > maxSuspensionReasonLength {
+		return nil, status.Errorf(codes.InvalidArgument, "reason must be at most %d characters, got %d", maxSuspensionReasonLength, n)
+	}
+
+	var until time.Time
+	if req.EndsAt != nil {
+		if err := req.EndsAt.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ends_at: %v", err)
+		}
+		until = req.EndsAt.AsTime()
+		if !until.After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "ends_at must be in the future")
+		}
+	}
+
+	suspension, err := c.h.SuspendUser(ctx, target, admin, reason, until)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	// without a refresh token the user is signed out once their access token lapses
+	if err := c.refreshTokens.DeleteRefreshToken(ctx, target.String()); err != nil {
+		c.observeError(op, "redis")
+		return nil, status.Errorf(codes.Internal, "user suspended but failed to revoke refresh token: %v", err)
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.SuspendUserResponse{Suspension: suspension}, nil
+}
+
+// --- UNSUSPEND USER ---
+func (c *UserController) UnsuspendUser(ctx context.Context, req *userv1.UnsuspendUserRequest) (*userv1.UnsuspendUserResponse, error) {
+	start := time.Now()
+	const op = "unsuspend_user"
+
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	target, err := parseUUID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.h.UnsuspendUser(ctx, target); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.UnsuspendUserResponse{}, nil
+}
+
+// getUserAdminView returns the unredacted user alongside any suspension.
+func (c *UserController) getUserAdminView(ctx context.Context, id string) (*userv1.GetUserResponse, error) {
+	start := time.Now()
+	const op = "get_user_admin"
+
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	userID, err := parseUUID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := c.h.GetUser(ctx, id)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	suspension, err := c.h.Suspension(ctx, userID)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.GetUserResponse{User: user, Suspension: suspension}, nil
+}
+
+// requireAdmin returns the id of the calling admin, rejecting anyone else.
+func requireAdmin(ctx context.Context) (gocql.UUID, error) {
+	claims, ok := authjwt.ClaimsFromContext(ctx)
+	if !ok || !slices.Contains(claims.Roles, adminRole) {
+		return gocql.UUID{}, status.Error(codes.PermissionDenied, "admin role required")
+	}
+	id, err := gocql.ParseUUID(claims.UserID)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.Unauthenticated, "invalid caller id: %v", err)
+	}
+	return id, nil
+}
@@ -2,39 +2,81 @@ package controller
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	authjwt "github.com/yaninyzwitty/chat/packages/auth/jwt"
 	"github.com/yaninyzwitty/chat/packages/shared/config"
 	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/shared/pagetoken"
+	"github.com/yaninyzwitty/chat/packages/user/avatar"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
 	"github.com/yaninyzwitty/chat/packages/user/handler"
+	"github.com/yaninyzwitty/chat/packages/user/presence"
+	"github.com/yaninyzwitty/chat/packages/user/profile"
+	"github.com/yaninyzwitty/chat/packages/user/usercache"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	defaultBatchGetMaxIDs      = 100
+	defaultBatchGetConcurrency = 8
+	defaultMaxPageSize         = 200
+)
+
 type UserController struct {
 	userv1.UnimplementedUserServiceServer
-	h      *handler.UserHandler
-	M      *monitoring.Metrics
-	Config *config.Config
+	h        *handler.UserHandler
+	blobs    avatar.BlobStore
+	presence *presence.Store
+	// refresh tokens live in the Redis shared with the auth service
+	refreshTokens *authjwt.RefreshTokenStore
+	pages         *pagetoken.Codec
+	changes       *changelog.Watcher
+	M             *monitoring.Metrics
+	Config        *config.Config
 }
 
-func NewUserController(ctx context.Context, cfg *config.Config, reg *prometheus.Registry, token string, db *gocql.Session) *UserController {
+func NewUserController(ctx context.Context, cfg *config.Config, reg *prometheus.Registry, token string, db *gocql.Session, blobs avatar.BlobStore, rdb *redis.Client, pages *pagetoken.Codec) *UserController {
 	m := monitoring.NewMetrics(reg)
 
 	h := handler.NewUserHandler(db) // handler only gets DB session
+	h.Cache = usercache.New(rdb, m, usercache.Config{
+		Size:        cfg.UserCache.Size,
+		LocalTTL:    time.Duration(cfg.UserCache.LocalTTL) * time.Second,
+		RedisTTL:    time.Duration(cfg.UserCache.RedisTTL) * time.Second,
+		NegativeTTL: time.Duration(cfg.UserCache.NegativeTTL) * time.Second,
+	})
 
 	return &UserController{
-		Config: cfg,
-		M:      m,
-		h:      h,
+		Config:   cfg,
+		M:        m,
+		h:        h,
+		blobs:    blobs,
+		presence: presence.NewStore(rdb, time.Duration(cfg.Presence.HeartbeatTTL)*time.Second),
+		pages:    pages,
+		changes: &changelog.Watcher{
+			Scan:         h.ScanChanges,
+			PollInterval: time.Duration(cfg.User.WatchPollInterval) * time.Second,
+		},
+
+		refreshTokens: authjwt.NewRefreshTokenStore(rdb),
 	}
 }
 
+// RunUserCache keeps this instance's cached users in step with invalidations
+// from other instances until ctx is cancelled.
+func (c *UserController) RunUserCache(ctx context.Context) error {
+	return c.h.Cache.Run(ctx)
+}
+
 // --- CREATE USER ---
 func (c *UserController) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.CreateUserResponse, error) {
 	start := time.Now()
@@ -82,14 +124,29 @@ func (c *UserController) GetUser(ctx context.Context, req *userv1.GetUserRequest
 		return nil, status.Error(codes.InvalidArgument, "user id is required")
 	}
 
+	if req.GetView() == userv1.UserView_USER_VIEW_ADMIN {
+		return c.getUserAdminView(ctx, req.Id)
+	}
+
 	user, err := c.h.GetUser(ctx, req.Id)
 	if err != nil {
 		c.observeError(op, "cassandra")
 		return nil, err
 	}
 
+	// blocked users only ever see each other's id
+	users, err := c.redactBlocked(ctx, []*userv1.User{user})
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if err := c.applyPrivacy(ctx, users); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
 	c.observeDuration(op, "cassandra", start)
-	return &userv1.GetUserResponse{User: user}, nil
+	return &userv1.GetUserResponse{User: users[0]}, nil
 }
 
 // --- LIST USERS ---
@@ -97,14 +154,186 @@ func (c *UserController) ListUsers(ctx context.Context, req *userv1.ListUsersReq
 	start := time.Now()
 	const op = "list_users"
 
-	usersResp, err := c.h.ListUsers(ctx, int32(req.GetPageLimit()), req.GetPageToken())
+	pageSize, pageState, err := c.decodePage(ctx, op, req.GetPageLimit(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	users, next, err := c.h.ListUsers(ctx, pageSize, pageState)
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	if users, err = c.filterBlocked(ctx, users); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if err := c.applyPrivacy(ctx, users); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.ListUsersResponse{Users: users, PageToken: c.encodePage(ctx, op, pageSize, next)}, nil
+}
+
+// --- BATCH GET USERS ---
+func (c *UserController) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersRequest) (*userv1.BatchGetUsersResponse, error) {
+	start := time.Now()
+	const op = "batch_get_users"
+
+	ids := uniqueIDs(req.GetIds())
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one user id is required")
+	}
+
+	if maxIDs := c.batchMaxIDs(); len(ids) > maxIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d user ids may be requested at once", maxIDs)
+	}
+
+	users, missing, err := c.h.BatchGetUsers(ctx, ids, c.batchConcurrency())
+	if err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	if users, err = c.redactBlocked(ctx, users); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+	if err := c.applyPrivacy(ctx, users); err != nil {
+		c.observeError(op, "cassandra")
+		return nil, err
+	}
+
+	c.observeDuration(op, "cassandra", start)
+	return &userv1.BatchGetUsersResponse{Users: users, MissingIds: missing}, nil
+}
+
+// --- UPDATE PROFILE ---
+func (c *UserController) UpdateProfile(ctx context.Context, req *userv1.UpdateProfileRequest) (*userv1.UpdateProfileResponse, error) {
+	start := time.Now()
+	const op = "update_profile"
+
+	caller, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := req.GetId()
+	if id == "" {
+		id = caller
+	}
+	if id != caller {
+		return nil, status.Error(codes.PermissionDenied, "users may only update their own profile")
+	}
+
+	if req.GetProfile() == nil {
+		return nil, status.Error(codes.InvalidArgument, "profile is required")
+	}
+
+	paths, err := profile.Paths(req.GetUpdateMask())
+	if err != nil {
+		return nil, err
+	}
+	if err := profile.Validate(req.GetProfile(), paths); err != nil {
+		return nil, err
+	}
+
+	user, err := c.h.UpdateProfile(ctx, id, req.GetProfile(), paths)
 	if err != nil {
 		c.observeError(op, "cassandra")
 		return nil, err
 	}
 
 	c.observeDuration(op, "cassandra", start)
-	return usersResp, nil
+	return &userv1.UpdateProfileResponse{User: user}, nil
+}
+
+// callerID returns the id of the authenticated user making the request.
+func callerID(ctx context.Context) (string, error) {
+	claims, ok := authjwt.ClaimsFromContext(ctx)
+	if !ok || claims.UserID == "" {
+		return "", status.Error(codes.Unauthenticated, "missing caller identity")
+	}
+	return claims.UserID, nil
+}
+
+// callerUUID is callerID parsed as a UUID.
+func callerUUID(ctx context.Context) (gocql.UUID, error) {
+	id, err := callerID(ctx)
+	if err != nil {
+		return gocql.UUID{}, err
+	}
+	uuid, err := gocql.ParseUUID(id)
+	if err != nil {
+		return gocql.UUID{}, status.Errorf(codes.Unauthenticated, "invalid caller id: %v", err)
+	}
+	return uuid, nil
+}
+
+// decodePage validates a list call's page token for the caller, returning the
+// page size to query with and the driver state to resume from.
+func (c *UserController) decodePage(ctx context.Context, scope string, pageLimit uint32, pageToken string) (int32, []byte, error) {
+	page, err := c.pages.Decode(pageScope(ctx, scope), pageToken)
+	if err != nil {
+		return 0, nil, err
+	}
+	return page.Limit(pageLimit, c.maxPageSize()), page.State, nil
+}
+
+// encodePage returns the page token for the page after the one just read.
+func (c *UserController) encodePage(ctx context.Context, scope string, pageSize int32, pageState []byte) string {
+	return c.pages.Encode(pageScope(ctx, scope), pageState, pageSize)
+}
+
+func pageScope(ctx context.Context, scope string) string {
+	if claims, ok := authjwt.ClaimsFromContext(ctx); ok {
+		return scope + ":" + claims.UserID
+	}
+	return scope
+}
+
+// maxPageSize is the largest page a list call may ask for.
+func (c *UserController) maxPageSize() int32 {
+	if n := c.Config.User.MaxPageSize; n > 0 {
+		return int32(min(n, math.MaxInt32))
+	}
+	return defaultMaxPageSize
+}
+
+// batchMaxIDs is the most ids a single batch lookup may carry.
+func (c *UserController) batchMaxIDs() int {
+	if n := c.Config.User.BatchGetMaxIDs; n > 0 {
+		return n
+	}
+	return defaultBatchGetMaxIDs
+}
+
+// batchConcurrency is how many lookups a batch runs against Cassandra at once.
+func (c *UserController) batchConcurrency() int {
+	if n := c.Config.User.BatchGetConcurrency; n > 0 {
+		return n
+	}
+	return defaultBatchGetConcurrency
+}
+
+// uniqueIDs drops empty and repeated ids while keeping the caller's order.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
 }
 
 // --- metrics helpers ---
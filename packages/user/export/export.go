@@ -0,0 +1,125 @@
+// Package export writes a user's data as a zip archive of JSON files, for
+// answering data-subject access requests.
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultChunkSize is how many bytes ChunkWriter hands to send at a time.
+const DefaultChunkSize = 64 * 1024
+
+// Archive writes files into a zip stream. Files are compressed and written
+// as they are added, so nothing but the current file is held in memory.
+type Archive struct {
+	zw       *zip.Writer
+	created  time.Time
+	manifest []string
+}
+
+// NewArchive starts an archive on w.
+func NewArchive(w io.Writer) *Archive {
+	return &Archive{zw: zip.NewWriter(w), created: time.Now()}
+}
+
+// AddJSON adds v as an indented JSON file. Proto messages are encoded with
+// protojson using their field names; anything else with encoding/json.
+func (a *Archive) AddJSON(name string, v any) error {
+	var (
+		data []byte
+		err  error
+	)
+	if m, ok := v.(proto.Message); ok {
+		data, err = protojson.MarshalOptions{Multiline: true, UseProtoNames: true, EmitUnpopulated: true}.Marshal(m)
+	} else {
+		data, err = json.MarshalIndent(v, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	return a.AddFile(name, data)
+}
+
+// AddFile adds data as is.
+func (a *Archive) AddFile(name string, data []byte) error {
+	f, err := a.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: a.created,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	a.manifest = append(a.manifest, name)
+	return nil
+}
+
+// Close writes a manifest describing the archive, then the zip directory.
+// subject is the id of the user the archive is about.
+func (a *Archive) Close(subject string) error {
+	manifest := struct {
+		UserID      string    `json:"user_id"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Files       []string  `json:"files"`
+	}{subject, a.created, a.manifest}
+
+	if err := a.AddJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+	return a.zw.Close()
+}
+
+// ChunkWriter buffers writes into chunks of Size bytes and passes each full
+// chunk to Send; Flush sends whatever remains.
+type ChunkWriter struct {
+	Size int
+	Send func([]byte) error
+
+	buf []byte
+}
+
+func (c *ChunkWriter) Write(p []byte) (int, error) {
+	size := c.Size
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	n := len(p)
+	for len(p) > 0 {
+		take := min(size-len(c.buf), len(p))
+		c.buf = append(c.buf, p[:take]...)
+		p = p[take:]
+		if len(c.buf) == size {
+			if err := c.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Flush sends any buffered bytes.
+func (c *ChunkWriter) Flush() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+	return c.flush()
+}
+
+func (c *ChunkWriter) flush() error {
+	// Send may keep the slice, so hand over a fresh buffer each time
+	chunk := c.buf
+	c.buf = nil
+	return c.Send(chunk)
+}
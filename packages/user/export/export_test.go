@@ -0,0 +1,56 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/export"
+)
+
+func TestArchive(t *testing.T) {
+	var chunks [][]byte
+	w := &export.ChunkWriter{Size: 100, Send: func(b []byte) error {
+		chunks = append(chunks, b)
+		return nil
+	}}
+
+	a := export.NewArchive(w)
+	require.NoError(t, a.AddJSON("profile.json", &userv1.User{Id: "u1", Name: "Alice"}))
+	require.NoError(t, a.AddJSON("tables/contacts.json", []map[string]any{{"contact_id": "u2"}}))
+	require.NoError(t, a.Close("u1"))
+	require.NoError(t, w.Flush())
+
+	require.Greater(t, len(chunks), 1)
+	for _, c := range chunks[:len(chunks)-1] {
+		require.Len(t, c, 100)
+	}
+
+	data := bytes.Join(chunks, nil)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+	var profile map[string]any
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	require.Equal(t, "Alice", profile["name"])
+
+	var manifest struct {
+		UserID string   `json:"user_id"`
+		Files  []string `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	require.Equal(t, "u1", manifest.UserID)
+	require.Equal(t, []string{"profile.json", "tables/contacts.json"}, manifest.Files)
+}
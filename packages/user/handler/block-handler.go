@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- DB INSERT BLOCK ---
+// BlockUser records that blocker blocked blocked and severs any contact or
+// pending contact request between them.
+func (h *UserHandler) BlockUser(ctx context.Context, blocker, blocked gocql.UUID) error {
+	now := time.Now()
+
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`INSERT INTO chat.user_blocks (user_id, blocked_id, created_at) VALUES (?, ?, ?)`, blocker, blocked, now)
+	batch.Query(`INSERT INTO chat.user_blocked_by (user_id, blocker_id, created_at) VALUES (?, ?, ?)`, blocked, blocker, now)
+	batch.Query(`DELETE FROM chat.contacts WHERE user_id = ? AND contact_id = ?`, blocker, blocked)
+	batch.Query(`DELETE FROM chat.contacts WHERE user_id = ? AND contact_id = ?`, blocked, blocker)
+	batch.Query(`DELETE FROM chat.contact_requests WHERE user_id = ? AND requester_id = ?`, blocker, blocked)
+	batch.Query(`DELETE FROM chat.contact_requests WHERE user_id = ? AND requester_id = ?`, blocked, blocker)
+	batch.Query(`DELETE FROM chat.sent_contact_requests WHERE user_id = ? AND recipient_id = ?`, blocker, blocked)
+	batch.Query(`DELETE FROM chat.sent_contact_requests WHERE user_id = ? AND recipient_id = ?`, blocked, blocker)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to block user: %v", err)
+	}
+	return nil
+}
+
+// --- DB DELETE BLOCK ---
+func (h *UserHandler) UnblockUser(ctx context.Context, blocker, blocked gocql.UUID) error {
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`DELETE FROM chat.user_blocks WHERE user_id = ? AND blocked_id = ?`, blocker, blocked)
+	batch.Query(`DELETE FROM chat.user_blocked_by WHERE user_id = ? AND blocker_id = ?`, blocked, blocker)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to unblock user: %v", err)
+	}
+	return nil
+}
+
+// --- DB LIST BLOCKS ---
+func (h *UserHandler) ListBlockedUsers(ctx context.Context, userID gocql.UUID, pageLimit int32, pageToken []byte) ([]*userv1.BlockedUser, []byte, error) {
+	iter := h.pagedQuery(ctx,
+		`SELECT blocked_id, created_at FROM chat.user_blocks WHERE user_id = ?`,
+		pageLimit, pageToken, userID,
+	).Iter()
+
+	var (
+		blocked   []*userv1.BlockedUser
+		blockedID gocql.UUID
+		createdAt time.Time
+	)
+	for iter.Scan(&blockedID, &createdAt) {
+		blocked = append(blocked, &userv1.BlockedUser{
+			UserId:    blockedID.String(),
+			CreatedAt: timestamppb.New(createdAt),
+		})
+	}
+
+	nextPage := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to list blocked users: %v", err)
+	}
+	return blocked, nextPage, nil
+}
+
+// --- DB INSERT MUTE ---
+// MuteUser mutes muted for userID; a non-zero ttl lets the mute lapse on its own.
+func (h *UserHandler) MuteUser(ctx context.Context, userID, muted gocql.UUID, ttl time.Duration) error {
+	if err := h.Db.Query(
+		`INSERT INTO chat.user_mutes (user_id, muted_id, created_at) VALUES (?, ?, ?) USING TTL ?`,
+		userID, muted, time.Now(), int(ttl.Seconds()),
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to mute user: %v", err)
+	}
+	return nil
+}
+
+// --- DB DELETE MUTE ---
+func (h *UserHandler) UnmuteUser(ctx context.Context, userID, muted gocql.UUID) error {
+	if err := h.Db.Query(
+		`DELETE FROM chat.user_mutes WHERE user_id = ? AND muted_id = ?`,
+		userID, muted,
+	).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmute user: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- DB SELECT BY EMAIL ---
+func (h *UserHandler) EmailExists(ctx context.Context, email string) (bool, error) {
+	var id gocql.UUID
+	err := h.Db.Query(
+		`SELECT id FROM chat.users WHERE email = ? LIMIT 1`,
+		email,
+	).WithContext(ctx).Consistency(gocql.One).Scan(&id)
+	if err == gocql.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to query email: %v", err)
+	}
+	return true, nil
+}
+
+// --- DB IMPORT ---
+// ImportUser inserts a user carrying its own timestamps and an existing
+// bcrypt hash, as migrated from another system. Unlike other guarded
+// mutations it logs the change only once the insert applied, so an id that
+// is already taken never shows up to watchers as created.
+func (h *UserHandler) ImportUser(ctx context.Context, user *userv1.User, passwordHash string) error {
+	userID, err := gocql.ParseUUID(user.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	// IF NOT EXISTS keeps a re-used id from overwriting someone else
+	applied, err := h.Db.Query(
+		`INSERT INTO chat.users (id, name, alias_name, email, password, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`,
+		userID, user.Name, user.AliasName, user.Email, passwordHash,
+		user.CreatedAt.AsTime(), user.UpdatedAt.AsTime(),
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to import user: %v", err)
+	}
+	if !applied {
+		return status.Errorf(codes.AlreadyExists, "user %s already exists", user.Id)
+	}
+	h.invalidate(ctx, userID)
+	return h.logChange(ctx, userID, changelog.KindCreated, nil)
+}
+
+// --- DB SCAN ALL ---
+// ExportUsers calls fn with every user, paging through the whole table.
+func (h *UserHandler) ExportUsers(ctx context.Context, pageSize int, fn func(*userv1.User) error) error {
+	iter := h.Db.Query(`SELECT ` + userColumns + ` FROM chat.users`).
+		WithContext(ctx).PageSize(pageSize).Iter()
+
+	var row userRow
+	for iter.Scan(row.dest()...) {
+		if err := fn(row.toProto()); err != nil {
+			_ = iter.Close()
+			return err
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to scan users: %v", err)
+	}
+	return nil
+}
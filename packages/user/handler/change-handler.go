@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const insertChange = `INSERT INTO chat.user_changes (bucket, change_id, user_id, kind, fields) VALUES (?, ?, ?, ?, ?)`
+
+// changeValues binds insertChange for a new change to userID.
+func changeValues(userID gocql.UUID, kind changelog.Kind, fields []string) []any {
+	id := gocql.TimeUUID()
+	return []any{changelog.Bucket(id.Time()), id, userID, string(kind), fields}
+}
+
+// --- DB INSERT CHANGE ---
+// logChange appends a change to the user change log. Mutations guarded by a
+// lightweight transaction cannot share a batch with it, so they log first: a
+// mutation that then fails leaves a spurious entry, which watchers tolerate
+// as they read the user's current state, instead of a lost one.
+func (h *UserHandler) logChange(ctx context.Context, userID gocql.UUID, kind changelog.Kind, fields []string) error {
+	if err := h.Db.Query(insertChange, changeValues(userID, kind, fields)...).WithContext(ctx).Exec(); err != nil {
+		return status.Errorf(codes.Internal, "failed to log user change: %v", err)
+	}
+	return nil
+}
+
+// --- DB SCAN CHANGES ---
+// ScanChanges calls fn, oldest first, with the changes in bucket after the
+// time UUID after. It satisfies changelog.ScanFunc.
+func (h *UserHandler) ScanChanges(ctx context.Context, bucket string, after gocql.UUID, fn func(changelog.Entry) error) error {
+	iter := h.Db.Query(
+		`SELECT change_id, user_id, kind, fields FROM chat.user_changes WHERE bucket = ? AND change_id > ?`,
+		bucket, after,
+	).WithContext(ctx).Iter()
+
+	var (
+		e    changelog.Entry
+		kind string
+	)
+	for iter.Scan(&e.ID, &e.UserID, &kind, &e.Fields) {
+		e.Kind = changelog.Kind(kind)
+		if err := fn(e); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		e.Fields = nil
+	}
+
+	if err := iter.Close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to scan user changes: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- DB INSERT CONTACT REQUEST ---
+// SendContactRequest records a pending request from requester to recipient.
+func (h *UserHandler) SendContactRequest(ctx context.Context, requester, recipient gocql.UUID, message string) (*userv1.ContactRequest, error) {
+	isContact, err := h.exists(ctx,
+		`SELECT contact_id FROM chat.contacts WHERE user_id = ? AND contact_id = ?`, requester, recipient)
+	if err != nil {
+		return nil, err
+	}
+	if isContact {
+		return nil, status.Error(codes.AlreadyExists, "user is already a contact")
+	}
+
+	reversePending, err := h.exists(ctx,
+		`SELECT requester_id FROM chat.contact_requests WHERE user_id = ? AND requester_id = ?`, requester, recipient)
+	if err != nil {
+		return nil, err
+	}
+	if reversePending {
+		return nil, status.Error(codes.FailedPrecondition, "user has already sent you a contact request; accept it instead")
+	}
+
+	now := time.Now()
+
+	// LWT so concurrent duplicates resolve to a single request
+	applied, err := h.Db.Query(
+		`INSERT INTO chat.contact_requests (user_id, requester_id, message, created_at)
+		 VALUES (?, ?, ?, ?) IF NOT EXISTS`,
+		recipient, requester, message, now,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to insert contact request: %v", err)
+	}
+	if !applied {
+		return nil, status.Error(codes.AlreadyExists, "contact request already sent")
+	}
+
+	if err := h.Db.Query(
+		`INSERT INTO chat.sent_contact_requests (user_id, recipient_id, message, created_at)
+		 VALUES (?, ?, ?, ?)`,
+		requester, recipient, message, now,
+	).WithContext(ctx).Exec(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to insert sent contact request: %v", err)
+	}
+
+	return &userv1.ContactRequest{
+		RequesterId: requester.String(),
+		RecipientId: recipient.String(),
+		Message:     message,
+		CreatedAt:   timestamppb.New(now),
+	}, nil
+}
+
+// --- DB ACCEPT CONTACT REQUEST ---
+// AcceptContactRequest turns requester's pending request to recipient into a
+// contact on both sides.
+func (h *UserHandler) AcceptContactRequest(ctx context.Context, recipient, requester gocql.UUID) (*userv1.Contact, error) {
+	if err := h.requirePendingRequest(ctx, recipient, requester); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`INSERT INTO chat.contacts (user_id, contact_id, created_at) VALUES (?, ?, ?)`, recipient, requester, now)
+	batch.Query(`INSERT INTO chat.contacts (user_id, contact_id, created_at) VALUES (?, ?, ?)`, requester, recipient, now)
+	batch.Query(`DELETE FROM chat.contact_requests WHERE user_id = ? AND requester_id = ?`, recipient, requester)
+	batch.Query(`DELETE FROM chat.sent_contact_requests WHERE user_id = ? AND recipient_id = ?`, requester, recipient)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to accept contact request: %v", err)
+	}
+
+	return &userv1.Contact{UserId: requester.String(), CreatedAt: timestamppb.New(now)}, nil
+}
+
+// --- DB DECLINE CONTACT REQUEST ---
+func (h *UserHandler) DeclineContactRequest(ctx context.Context, recipient, requester gocql.UUID) error {
+	if err := h.requirePendingRequest(ctx, recipient, requester); err != nil {
+		return err
+	}
+
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`DELETE FROM chat.contact_requests WHERE user_id = ? AND requester_id = ?`, recipient, requester)
+	batch.Query(`DELETE FROM chat.sent_contact_requests WHERE user_id = ? AND recipient_id = ?`, requester, recipient)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to decline contact request: %v", err)
+	}
+	return nil
+}
+
+// --- DB DELETE CONTACT ---
+func (h *UserHandler) RemoveContact(ctx context.Context, userID, contactID gocql.UUID) error {
+	isContact, err := h.exists(ctx,
+		`SELECT contact_id FROM chat.contacts WHERE user_id = ? AND contact_id = ?`, userID, contactID)
+	if err != nil {
+		return err
+	}
+	if !isContact {
+		return status.Error(codes.NotFound, "contact not found")
+	}
+
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`DELETE FROM chat.contacts WHERE user_id = ? AND contact_id = ?`, userID, contactID)
+	batch.Query(`DELETE FROM chat.contacts WHERE user_id = ? AND contact_id = ?`, contactID, userID)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to remove contact: %v", err)
+	}
+	return nil
+}
+
+// --- DB LIST CONTACTS ---
+func (h *UserHandler) ListContacts(ctx context.Context, userID gocql.UUID, pageLimit int32, pageToken []byte) ([]*userv1.Contact, []byte, error) {
+	iter := h.pagedQuery(ctx,
+		`SELECT contact_id, created_at FROM chat.contacts WHERE user_id = ?`,
+		pageLimit, pageToken, userID,
+	).Iter()
+
+	var (
+		contacts  []*userv1.Contact
+		contactID gocql.UUID
+		createdAt time.Time
+	)
+	for iter.Scan(&contactID, &createdAt) {
+		contacts = append(contacts, &userv1.Contact{
+			UserId:    contactID.String(),
+			CreatedAt: timestamppb.New(createdAt),
+		})
+	}
+
+	nextPage := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to list contacts: %v", err)
+	}
+	return contacts, nextPage, nil
+}
+
+// --- DB LIST CONTACT REQUESTS ---
+// ListPendingRequests lists requests userID received, or sent when outgoing.
+func (h *UserHandler) ListPendingRequests(ctx context.Context, userID gocql.UUID, outgoing bool, pageLimit int32, pageToken []byte) ([]*userv1.ContactRequest, []byte, error) {
+	stmt := `SELECT requester_id, message, created_at FROM chat.contact_requests WHERE user_id = ?`
+	if outgoing {
+		stmt = `SELECT recipient_id, message, created_at FROM chat.sent_contact_requests WHERE user_id = ?`
+	}
+	iter := h.pagedQuery(ctx, stmt, pageLimit, pageToken, userID).Iter()
+
+	var (
+		requests  []*userv1.ContactRequest
+		otherID   gocql.UUID
+		message   string
+		createdAt time.Time
+	)
+	for iter.Scan(&otherID, &message, &createdAt) {
+		req := &userv1.ContactRequest{
+			RequesterId: otherID.String(),
+			RecipientId: userID.String(),
+			Message:     message,
+			CreatedAt:   timestamppb.New(createdAt),
+		}
+		if outgoing {
+			req.RequesterId, req.RecipientId = userID.String(), otherID.String()
+		}
+		requests = append(requests, req)
+	}
+
+	nextPage := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to list contact requests: %v", err)
+	}
+	return requests, nextPage, nil
+}
+
+func (h *UserHandler) requirePendingRequest(ctx context.Context, recipient, requester gocql.UUID) error {
+	pending, err := h.exists(ctx,
+		`SELECT requester_id FROM chat.contact_requests WHERE user_id = ? AND requester_id = ?`, recipient, requester)
+	if err != nil {
+		return err
+	}
+	if !pending {
+		return status.Error(codes.NotFound, "contact request not found")
+	}
+	return nil
+}
+
+// exists reports whether a single-row lookup by full primary key finds a row.
+func (h *UserHandler) exists(ctx context.Context, stmt string, values ...any) (bool, error) {
+	var key gocql.UUID
+	err := h.Db.Query(stmt, values...).WithContext(ctx).Consistency(gocql.One).Scan(&key)
+	if err == gocql.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to query: %v", err)
+	}
+	return true, nil
+}
+
+// pagedQuery returns a query fetching a single page of pageLimit rows starting
+// at pageToken. Setting PageState, even to nil, stops gocql from transparently
+// fetching the following pages while iterating.
+func (h *UserHandler) pagedQuery(ctx context.Context, stmt string, pageLimit int32, pageToken []byte, values ...any) *gocql.Query {
+	pageSize := int(pageLimit)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	return h.Db.Query(stmt, values...).WithContext(ctx).PageSize(pageSize).PageState(pageToken)
+}
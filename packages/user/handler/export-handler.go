@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PersonalTables are the tables partitioned by user_id whose rows belong to
+// that user and so go into their data export. Tables recording what other
+// users did to them, such as user_blocked_by, are left out.
+var PersonalTables = []string{
+	"user_settings",
+	"contacts",
+	"contact_requests",
+	"sent_contact_requests",
+	"user_blocks",
+	"user_mutes",
+	"user_conversations",
+	"user_reactions",
+	"followed_threads",
+	"followed_threads_by_activity",
+}
+
+// --- DB SELECT EXPORT ---
+// ExportUserRow returns every column of id's chat.users row except the
+// password hash.
+func (h *UserHandler) ExportUserRow(ctx context.Context, id gocql.UUID) (map[string]any, error) {
+	row := map[string]any{}
+	if err := h.Db.Query(
+		`SELECT * FROM chat.users WHERE id = ?`,
+		id,
+	).WithContext(ctx).Consistency(gocql.One).MapScan(row); err != nil {
+		if err == gocql.ErrNotFound {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to query user: %v", err)
+	}
+
+	delete(row, "password")
+	return exportRow(row), nil
+}
+
+// ExportTableRows returns all of userID's rows in one of PersonalTables.
+func (h *UserHandler) ExportTableRows(ctx context.Context, table string, userID gocql.UUID) ([]map[string]any, error) {
+	// table only ever comes from PersonalTables, never from a request
+	return h.exportRows(ctx, table, `SELECT * FROM chat.`+table+` WHERE user_id = ?`, userID)
+}
+
+// ExportConversationIDs returns the conversations userID is a member of.
+func (h *UserHandler) ExportConversationIDs(ctx context.Context, userID gocql.UUID) ([]gocql.UUID, error) {
+	iter := h.Db.Query(
+		`SELECT conversation_id FROM chat.user_conversations WHERE user_id = ?`,
+		userID,
+	).WithContext(ctx).Iter()
+
+	// a conversation can be listed more than once until stale rows are dropped
+	seen := map[gocql.UUID]bool{}
+	var ids []gocql.UUID
+	var id gocql.UUID
+	for iter.Scan(&id) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query conversations: %v", err)
+	}
+	return ids, nil
+}
+
+// ExportMessages returns the messages userID sent in conversation convID.
+func (h *UserHandler) ExportMessages(ctx context.Context, convID, userID gocql.UUID) ([]map[string]any, error) {
+	iter := h.Db.Query(
+		`SELECT bucket FROM chat.message_buckets WHERE conversation_id = ?`,
+		convID,
+	).WithContext(ctx).Iter()
+	var buckets []string
+	var bucket string
+	for iter.Scan(&bucket) {
+		buckets = append(buckets, bucket)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query message buckets: %v", err)
+	}
+
+	messages := []map[string]any{}
+	for _, bucket := range buckets {
+		// filtering stays within one partition
+		rows, err := h.exportRows(ctx, "messages",
+			`SELECT * FROM chat.messages WHERE conversation_id = ? AND bucket = ? AND sender_id = ? ALLOW FILTERING`,
+			convID, bucket, userID)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, rows...)
+	}
+	return messages, nil
+}
+
+// ExportReceipts returns userID's read and delivery receipt in conversation
+// convID.
+func (h *UserHandler) ExportReceipts(ctx context.Context, convID, userID gocql.UUID) ([]map[string]any, error) {
+	return h.exportRows(ctx, "message_receipts",
+		`SELECT * FROM chat.message_receipts WHERE conversation_id = ? AND user_id = ?`,
+		convID, userID)
+}
+
+// exportRows returns every row stmt selects, naming table in errors.
+func (h *UserHandler) exportRows(ctx context.Context, table, stmt string, values ...any) ([]map[string]any, error) {
+	iter := h.Db.Query(stmt, values...).WithContext(ctx).Iter()
+
+	rows := []map[string]any{}
+	for {
+		row := map[string]any{}
+		if !iter.MapScan(row) {
+			break
+		}
+		rows = append(rows, exportRow(row))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query %s: %v", table, err)
+	}
+	return rows, nil
+}
+
+// exportRow drops unset timestamps, which gocql reports as the zero time,
+// so they read as null rather than year 1.
+func exportRow(row map[string]any) map[string]any {
+	for k, v := range row {
+		if t, ok := v.(time.Time); ok && t.IsZero() {
+			row[k] = nil
+		}
+	}
+	return row
+}
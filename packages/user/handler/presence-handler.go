@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- DB UPDATE LAST SEEN ---
+func (h *UserHandler) SetLastSeen(ctx context.Context, id string, at time.Time) error {
+	userID, err := gocql.ParseUUID(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	// plain UPDATE would upsert a row for a deleted user; IF EXISTS avoids that.
+	// last_seen_at is not part of the cached user, so nothing is invalidated.
+	if _, err := h.Db.Query(
+		`UPDATE chat.users SET last_seen_at = ? WHERE id = ? IF EXISTS`,
+		at, userID,
+	).WithContext(ctx).MapScanCAS(map[string]any{}); err != nil {
+		return status.Errorf(codes.Internal, "failed to update last seen: %v", err)
+	}
+	return nil
+}
+
+// --- DB SELECT LAST SEEN ---
+// LastSeen returns the persisted last-seen time for each id that has one.
+func (h *UserHandler) LastSeen(ctx context.Context, ids []string, concurrency int) (map[string]time.Time, error) {
+	seen := make([]time.Time, len(ids))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	for i, id := range ids {
+		g.Go(func() error {
+			userID, err := gocql.ParseUUID(id)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "invalid UUID %q: %v", id, err)
+			}
+			err = h.Db.Query(
+				`SELECT last_seen_at FROM chat.users WHERE id = ?`,
+				userID,
+			).WithContext(gctx).Consistency(gocql.One).Scan(&seen[i])
+			if err != nil && err != gocql.ErrNotFound {
+				return status.Errorf(codes.Internal, "failed to query last seen: %v", err)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]time.Time, len(ids))
+	for i, at := range seen {
+		if !at.IsZero() {
+			out[ids[i]] = at
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// settingColumns maps the typed settings paths to their chat.user_settings column.
+var settingColumns = map[string]string{
+	"theme":                       "theme",
+	"locale":                      "locale",
+	"notifications.push_enabled":  "push_enabled",
+	"notifications.email_enabled": "email_enabled",
+	"notifications.sound_enabled": "sound_enabled",
+	"notifications.show_previews": "show_previews",
+	"privacy.hide_email":          "hide_email",
+	"privacy.hide_last_seen":      "hide_last_seen",
+}
+
+// --- DB SELECT SETTINGS ---
+// GetSettings returns userID's settings, taking any setting the user never
+// changed from defaults.
+func (h *UserHandler) GetSettings(ctx context.Context, userID gocql.UUID, defaults *userv1.Settings) (*userv1.Settings, error) {
+	s := proto.Clone(defaults).(*userv1.Settings)
+
+	var (
+		theme                        *int
+		locale                       *string
+		push, email, sound, previews *bool
+		hideEmail, hideLastSeen      *bool
+		preferences                  map[string]string
+		updatedAt                    time.Time
+	)
+	err := h.Db.Query(
+		`SELECT theme, locale, push_enabled, email_enabled, sound_enabled, show_previews,
+			hide_email, hide_last_seen, preferences, updated_at
+		 FROM chat.user_settings WHERE user_id = ?`,
+		userID,
+	).WithContext(ctx).Consistency(gocql.One).Scan(
+		&theme, &locale, &push, &email, &sound, &previews,
+		&hideEmail, &hideLastSeen, &preferences, &updatedAt,
+	)
+	if err == gocql.ErrNotFound {
+		return s, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query settings: %v", err)
+	}
+
+	if theme != nil {
+		s.Theme = userv1.Theme(*theme)
+	}
+	if locale != nil {
+		s.Locale = *locale
+	}
+	setBool(&s.Notifications.PushEnabled, push)
+	setBool(&s.Notifications.EmailEnabled, email)
+	setBool(&s.Notifications.SoundEnabled, sound)
+	setBool(&s.Notifications.ShowPreviews, previews)
+	setBool(&s.Privacy.HideEmail, hideEmail)
+	setBool(&s.Privacy.HideLastSeen, hideLastSeen)
+	if preferences != nil {
+		s.Preferences = preferences
+	}
+	if !updatedAt.IsZero() {
+		s.UpdatedAt = timestamppb.New(updatedAt)
+	}
+
+	return s, nil
+}
+
+// --- DB UPDATE SETTINGS ---
+// UpdateSettings writes the fields of s named in paths. Clearing theme or
+// locale reverts it to the default; a preference path whose key is missing
+// from s removes that preference.
+func (h *UserHandler) UpdateSettings(ctx context.Context, userID gocql.UUID, s *userv1.Settings, paths []string) error {
+	sets := []string{"updated_at = ?"}
+	values := []any{time.Now()}
+	var removed []string
+
+	for _, path := range paths {
+		switch {
+		case path == settings.PreferencesPath:
+			sets = append(sets, "preferences = ?")
+			values = append(values, s.GetPreferences())
+		case strings.HasPrefix(path, settings.PreferencePrefix):
+			key := strings.TrimPrefix(path, settings.PreferencePrefix)
+			if value, ok := s.GetPreferences()[key]; ok {
+				sets = append(sets, "preferences[?] = ?")
+				values = append(values, key, value)
+			} else {
+				removed = append(removed, key)
+			}
+		default:
+			column, ok := settingColumns[path]
+			if !ok {
+				return status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+			}
+			sets = append(sets, column+" = ?")
+			values = append(values, settingValue(s, path))
+		}
+	}
+	values = append(values, userID)
+
+	// one partition, so the batch applies atomically
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(fmt.Sprintf(`UPDATE chat.user_settings SET %s WHERE user_id = ?`, strings.Join(sets, ", ")), values...)
+	for _, key := range removed {
+		batch.Query(`DELETE preferences[?] FROM chat.user_settings WHERE user_id = ?`, key, userID)
+	}
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
+		return status.Errorf(codes.Internal, "failed to update settings: %v", err)
+	}
+	return nil
+}
+
+// --- DB SELECT PRIVACY ---
+// PrivacySettings returns the privacy settings of each of ids, falling back
+// to defaults for users who never changed them.
+func (h *UserHandler) PrivacySettings(ctx context.Context, ids []gocql.UUID, defaults *userv1.PrivacySettings) (map[gocql.UUID]*userv1.PrivacySettings, error) {
+	out := make(map[gocql.UUID]*userv1.PrivacySettings, len(ids))
+	for _, id := range ids {
+		out[id] = proto.Clone(defaults).(*userv1.PrivacySettings)
+	}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	iter := h.Db.Query(
+		`SELECT user_id, hide_email, hide_last_seen FROM chat.user_settings WHERE user_id IN ?`,
+		ids,
+	).WithContext(ctx).Consistency(gocql.One).Iter()
+
+	var (
+		id                      gocql.UUID
+		hideEmail, hideLastSeen *bool
+	)
+	for iter.Scan(&id, &hideEmail, &hideLastSeen) {
+		if p, ok := out[id]; ok {
+			setBool(&p.HideEmail, hideEmail)
+			setBool(&p.HideLastSeen, hideLastSeen)
+		}
+		hideEmail, hideLastSeen = nil, nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query privacy settings: %v", err)
+	}
+	return out, nil
+}
+
+// settingValue returns the column value for a typed settings path; nil
+// clears the column so the default applies again.
+func settingValue(s *userv1.Settings, path string) any {
+	switch path {
+	case "theme":
+		if s.GetTheme() == userv1.Theme_THEME_UNSPECIFIED {
+			return nil
+		}
+		return int(s.GetTheme())
+	case "locale":
+		if s.GetLocale() == "" {
+			return nil
+		}
+		return s.GetLocale()
+	case "notifications.push_enabled":
+		return s.GetNotifications().GetPushEnabled()
+	case "notifications.email_enabled":
+		return s.GetNotifications().GetEmailEnabled()
+	case "notifications.sound_enabled":
+		return s.GetNotifications().GetSoundEnabled()
+	case "notifications.show_previews":
+		return s.GetNotifications().GetShowPreviews()
+	case "privacy.hide_email":
+		return s.GetPrivacy().GetHideEmail()
+	case "privacy.hide_last_seen":
+		return s.GetPrivacy().GetHideLastSeen()
+	}
+	return nil
+}
+
+func setBool(dst *bool, v *bool) {
+	if v != nil {
+		*dst = *v
+	}
+}
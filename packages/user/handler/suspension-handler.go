@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- DB UPDATE SUSPEND ---
+// SuspendUser records a suspension of id by admin, lasting until until or,
+// when until is zero, until lifted.
+func (h *UserHandler) SuspendUser(ctx context.Context, id, admin gocql.UUID, reason string, until time.Time) (*userv1.Suspension, error) {
+	now := time.Now()
+
+	var ends any
+	if !until.IsZero() {
+		ends = until
+	}
+
+	if err := h.logChange(ctx, id, changelog.KindSuspended, nil); err != nil {
+		return nil, err
+	}
+
+	applied, err := h.Db.Query(
+		`UPDATE chat.users SET suspended_at = ?, suspended_until = ?, suspension_reason = ?, suspended_by = ?
+		 WHERE id = ? IF EXISTS`,
+		now, ends, reason, admin, id,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to suspend user: %v", err)
+	}
+	if !applied {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	h.invalidate(ctx, id)
+
+	return suspensionProto(now, until, reason, admin), nil
+}
+
+// --- DB UPDATE UNSUSPEND ---
+func (h *UserHandler) UnsuspendUser(ctx context.Context, id gocql.UUID) error {
+	if err := h.logChange(ctx, id, changelog.KindUnsuspended, nil); err != nil {
+		return err
+	}
+
+	applied, err := h.Db.Query(
+		`UPDATE chat.users SET suspended_at = null, suspended_until = null, suspension_reason = null, suspended_by = null
+		 WHERE id = ? IF EXISTS`,
+		id,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to unsuspend user: %v", err)
+	}
+	if !applied {
+		return status.Error(codes.NotFound, "user not found")
+	}
+	h.invalidate(ctx, id)
+	return nil
+}
+
+// --- DB SELECT SUSPENSION ---
+// Suspension returns id's suspension, or nil if it is not currently suspended.
+func (h *UserHandler) Suspension(ctx context.Context, id gocql.UUID) (*userv1.Suspension, error) {
+	var (
+		suspendedAt, until time.Time
+		reason             string
+		admin              gocql.UUID
+	)
+	err := h.Db.Query(
+		`SELECT suspended_at, suspended_until, suspension_reason, suspended_by FROM chat.users WHERE id = ?`,
+		id,
+	).WithContext(ctx).Consistency(gocql.One).Scan(&suspendedAt, &until, &reason, &admin)
+	if err == gocql.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query suspension: %v", err)
+	}
+
+	// a lapsed suspension is left in place but no longer applies
+	if suspendedAt.IsZero() || (!until.IsZero() && !time.Now().Before(until)) {
+		return nil, nil
+	}
+	return suspensionProto(suspendedAt, until, reason, admin), nil
+}
+
+func suspensionProto(at, until time.Time, reason string, admin gocql.UUID) *userv1.Suspension {
+	s := &userv1.Suspension{
+		Reason:      reason,
+		SuspendedAt: timestamppb.New(at),
+		SuspendedBy: admin.String(),
+	}
+	if !until.IsZero() {
+		s.EndsAt = timestamppb.New(until)
+	}
+	return s
+}
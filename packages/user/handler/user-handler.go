@@ -2,17 +2,23 @@ package handler
 
 import (
 	"context"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
 	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
+	"github.com/yaninyzwitty/chat/packages/user/usercache"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UserHandler struct {
 	Db *gocql.Session
+	// optional; GetUser reads through it and mutations invalidate it
+	Cache *usercache.Cache
 }
 
 func NewUserHandler(db *gocql.Session) *UserHandler {
@@ -26,14 +32,25 @@ func (h *UserHandler) CreateUser(ctx context.Context, user *userv1.User, userPas
 	if user.Id == "" {
 		return status.Error(codes.InvalidArgument, "user ID cannot be empty")
 	}
+	userID, err := gocql.ParseUUID(user.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
 
-	if err := h.Db.Query(
+	// the change log entry lands atomically with the user
+	batch := h.Db.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(
 		`INSERT INTO chat.users (id, name, alias_name, created_at, updated_at, email, password) 
 		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		user.Id, user.Name, user.AliasName, now, now, user.Email, userPassword,
-	).Exec(); err != nil {
+		userID, user.Name, user.AliasName, now, now, user.Email, userPassword,
+	)
+	batch.Query(insertChange, changeValues(userID, changelog.KindCreated, nil)...)
+
+	if err := h.Db.ExecuteBatch(batch); err != nil {
 		return status.Errorf(codes.Internal, "failed to insert user: %v", err)
 	}
+	// drops a cached "not found" for the id
+	h.invalidate(ctx, userID)
 	return nil
 }
 
@@ -44,83 +61,174 @@ func (h *UserHandler) GetUser(ctx context.Context, id string) (*userv1.User, err
 		return nil, status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
 	}
 
-	var (
-		name      string
-		aliasName string
-		email     string
-		createdAt time.Time
-		updatedAt time.Time
-	)
+	if h.Cache == nil {
+		return h.getUser(ctx, userID)
+	}
+	return h.Cache.Get(ctx, userID.String(), func(ctx context.Context) (*userv1.User, error) {
+		return h.getUser(ctx, userID)
+	})
+}
 
+func (h *UserHandler) getUser(ctx context.Context, userID gocql.UUID) (*userv1.User, error) {
+	var row userRow
 	if err := h.Db.Query(
-		`SELECT name, alias_name, created_at, updated_at, email 
-		 FROM chat.users WHERE id = ?`,
+		`SELECT `+userColumns+` FROM chat.users WHERE id = ?`,
 		userID,
-	).Consistency(gocql.One).Scan(&name, &aliasName, &createdAt, &updatedAt, &email); err != nil {
+	).WithContext(ctx).Consistency(gocql.One).Scan(row.dest()...); err != nil {
 		if err == gocql.ErrNotFound {
 			return nil, status.Error(codes.NotFound, "user not found")
 		}
 		return nil, status.Errorf(codes.Internal, "failed to query user: %v", err)
 	}
 
-	return &userv1.User{
-		Id:        id,
-		Name:      name,
-		AliasName: aliasName,
-		Email:     email,
-		CreatedAt: timestamppb.New(createdAt),
-		UpdatedAt: timestamppb.New(updatedAt),
-	}, nil
+	return row.toProto(), nil
 }
 
-// --- DB LIST ---
-func (h *UserHandler) ListUsers(ctx context.Context, pageLimit int32, pageToken []byte) (*userv1.ListUsersResponse, error) {
-	pageSize := int(pageLimit)
-	if pageSize <= 0 {
-		pageSize = 10
+// --- DB BATCH SELECT ---
+// BatchGetUsers resolves ids with at most concurrency lookups in flight and
+// returns the users found (in request order) alongside the ids that were not.
+func (h *UserHandler) BatchGetUsers(ctx context.Context, ids []string, concurrency int) ([]*userv1.User, []string, error) {
+	// reject malformed ids before issuing any query
+	for _, id := range ids {
+		if _, err := gocql.ParseUUID(id); err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid UUID %q: %v", id, err)
+		}
+	}
+
+	found := make([]*userv1.User, len(ids))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	for i, id := range ids {
+		g.Go(func() error {
+			user, err := h.GetUser(gctx, id)
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			found[i] = user
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	users := make([]*userv1.User, 0, len(ids))
+	var missing []string
+	for i, user := range found {
+		if user == nil {
+			missing = append(missing, ids[i])
+			continue
+		}
+		users = append(users, user)
+	}
+
+	return users, missing, nil
+}
+
+// --- DB UPDATE PROFILE ---
+// UpdateProfile writes the profile fields named in paths and bumps updated_at.
+// paths must already be validated against the editable profile fields.
+func (h *UserHandler) UpdateProfile(ctx context.Context, id string, profile *userv1.User, paths []string) (*userv1.User, error) {
+	userID, err := gocql.ParseUUID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid UUID: %v", err)
+	}
+
+	sets := make([]string, 0, len(paths)+1)
+	values := make([]any, 0, len(paths)+2)
+	for _, path := range paths {
+		value, err := profileValue(profile, path)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, path+" = ?")
+		values = append(values, value)
+	}
+	sets = append(sets, "updated_at = ?")
+	values = append(values, time.Now(), userID)
+
+	if err := h.logChange(ctx, userID, changelog.KindUpdated, paths); err != nil {
+		return nil, err
+	}
+
+	applied, err := h.Db.Query(
+		`UPDATE chat.users SET `+strings.Join(sets, ", ")+` WHERE id = ? IF EXISTS`,
+		values...,
+	).WithContext(ctx).MapScanCAS(map[string]any{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update profile: %v", err)
+	}
+	if !applied {
+		return nil, status.Error(codes.NotFound, "user not found")
 	}
 
-	// ✅ LIMIT added to enforce strict row count (fixes test failure)
-	q := h.Db.Query(
-		`SELECT id, name, alias_name, created_at, updated_at, email FROM chat.users LIMIT ?`,
-		pageSize,
-	).PageSize(pageSize)
+	h.invalidate(ctx, userID)
+	return h.GetUser(ctx, id)
+}
 
-	if len(pageToken) > 0 {
-		q = q.PageState(pageToken)
+// profileValue maps an editable profile path to the value bound for its column.
+func profileValue(profile *userv1.User, path string) (any, error) {
+	switch path {
+	case "name":
+		return profile.GetName(), nil
+	case "alias_name":
+		return profile.GetAliasName(), nil
+	case "bio":
+		return profile.GetBio(), nil
+	case "status_text":
+		return profile.GetStatusText(), nil
+	case "status_emoji":
+		return profile.GetStatusEmoji(), nil
+	case "status_expires_at":
+		// a nil timestamp clears the expiry
+		if profile.GetStatusExpiresAt() == nil {
+			return nil, nil
+		}
+		return profile.GetStatusExpiresAt().AsTime(), nil
+	case "timezone":
+		return profile.GetTimezone(), nil
+	case "locale":
+		return profile.GetLocale(), nil
+	case "avatar_url":
+		return profile.GetAvatarUrl(), nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "field %q is not editable", path)
 	}
+}
 
-	iter := q.Iter()
+// --- DB LIST ---
+func (h *UserHandler) ListUsers(ctx context.Context, pageLimit int32, pageToken []byte) ([]*userv1.User, []byte, error) {
+	iter := h.pagedQuery(ctx, `SELECT `+userColumns+` FROM chat.users`, pageLimit, pageToken).Iter()
 
 	var users []*userv1.User
-	var (
-		id        gocql.UUID
-		name      string
-		aliasName string
-		createdAt time.Time
-		updatedAt time.Time
-		email     string
-	)
+	var row userRow
 
-	for iter.Scan(&id, &name, &aliasName, &createdAt, &updatedAt, &email) {
-		users = append(users, &userv1.User{
-			Id:        id.String(),
-			Name:      name,
-			AliasName: aliasName,
-			Email:     email,
-			CreatedAt: timestamppb.New(createdAt),
-			UpdatedAt: timestamppb.New(updatedAt),
-		})
+	for iter.Scan(row.dest()...) {
+		users = append(users, row.toProto())
 	}
 
 	nextPage := iter.PageState()
 
 	if err := iter.Close(); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+		return nil, nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
 	}
 
-	return &userv1.ListUsersResponse{
-		Users:     users,
-		PageToken: nextPage,
-	}, nil
+	return users, nextPage, nil
+}
+
+// invalidate drops userID from the cache after a write. The write has already
+// succeeded, so a failure is only logged; the cache TTLs bound the staleness.
+func (h *UserHandler) invalidate(ctx context.Context, userID gocql.UUID) {
+	if h.Cache == nil {
+		return
+	}
+	if err := h.Cache.Invalidate(ctx, userID.String()); err != nil {
+		slog.Warn("failed to invalidate cached user", "user_id", userID, "error", err)
+	}
 }
@@ -4,11 +4,19 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/require"
 	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/blocklist"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/user/changelog"
 	"github.com/yaninyzwitty/chat/packages/user/handler"
+	"github.com/yaninyzwitty/chat/packages/user/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestCreateUser(t *testing.T) {
@@ -170,15 +178,340 @@ func TestListUsers(t *testing.T) {
 			require.NoError(t, tc.setup(ctx, db))
 
 			h := handler.NewUserHandler(db)
-			res, err := h.ListUsers(ctx, tc.limit, tc.pageToken)
+			users, next, err := h.ListUsers(ctx, tc.limit, tc.pageToken)
 
 			if tc.errors {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				require.Len(t, res.Users, tc.expectLen)
-				require.NotNil(t, res.PageToken)
+				require.Len(t, users, tc.expectLen)
+				require.NotNil(t, next)
 			}
 		})
 	}
 }
+
+func TestBatchGetUsers(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	existing := []gocql.UUID{gocql.TimeUUID(), gocql.TimeUUID()}
+	absent := gocql.TimeUUID()
+
+	testCases := []struct {
+		name       string
+		setup      func(ctx context.Context, db *gocql.Session) error
+		ids        []string
+		expectLen  int
+		expectMiss []string
+		errors     bool
+	}{
+		{
+			name: "success:found_and_missing",
+			setup: func(ctx context.Context, db *gocql.Session) error {
+				for i, id := range existing {
+					if err := db.Query(`INSERT INTO chat.users (id, name, email, alias_name, created_at, updated_at, password)
+						VALUES (?, ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?)`,
+						id, fmt.Sprintf("User%d", i), fmt.Sprintf("b%d@example.com", i), "Alias", "pwd").Exec(); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			ids:        []string{existing[0].String(), absent.String(), existing[1].String()},
+			expectLen:  2,
+			expectMiss: []string{absent.String()},
+			errors:     false,
+		},
+		{
+			name:   "error:invalid_uuid",
+			setup:  func(ctx context.Context, db *gocql.Session) error { return nil },
+			ids:    []string{existing[0].String(), "not-a-uuid"},
+			errors: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.setup(ctx, db))
+
+			h := handler.NewUserHandler(db)
+			users, missing, err := h.BatchGetUsers(ctx, tc.ids, 4)
+
+			if tc.errors {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, users, tc.expectLen)
+				require.Equal(t, tc.expectMiss, missing)
+			}
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	validID := gocql.TimeUUID()
+
+	testCases := []struct {
+		name    string
+		setup   func(ctx context.Context, db *gocql.Session) error
+		id      string
+		profile *userv1.User
+		paths   []string
+		errors  bool
+	}{
+		{
+			name: "success:update_bio_and_timezone",
+			setup: func(ctx context.Context, db *gocql.Session) error {
+				return db.Query(`INSERT INTO chat.users (id, name, email, alias_name, created_at, updated_at, password)
+					VALUES (?, ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?)`,
+					validID, "Alice", "alice@example.com", "Ali", "pwd").Exec()
+			},
+			id:      validID.String(),
+			profile: &userv1.User{Bio: "hello there", Timezone: "Africa/Nairobi"},
+			paths:   []string{"bio", "timezone"},
+			errors:  false,
+		},
+		{
+			name:    "error:user_not_found",
+			setup:   func(ctx context.Context, db *gocql.Session) error { return nil },
+			id:      gocql.TimeUUID().String(),
+			profile: &userv1.User{Bio: "ghost"},
+			paths:   []string{"bio"},
+			errors:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.setup(ctx, db))
+
+			h := handler.NewUserHandler(db)
+			user, err := h.UpdateProfile(ctx, tc.id, tc.profile, tc.paths)
+
+			if tc.errors {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.profile.Bio, user.Bio)
+				require.Equal(t, tc.profile.Timezone, user.Timezone)
+				require.Equal(t, "Alice", user.Name)
+			}
+		})
+	}
+}
+
+func TestContactRequestFlow(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	h := handler.NewUserHandler(db)
+	alice, bob := gocql.TimeUUID(), gocql.TimeUUID()
+
+	_, err = h.SendContactRequest(ctx, alice, bob, "hi bob")
+	require.NoError(t, err)
+
+	// duplicates and crossed requests are rejected
+	_, err = h.SendContactRequest(ctx, alice, bob, "hi again")
+	require.Error(t, err)
+	_, err = h.SendContactRequest(ctx, bob, alice, "hi alice")
+	require.Error(t, err)
+
+	incoming, _, err := h.ListPendingRequests(ctx, bob, false, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, incoming, 1)
+	require.Equal(t, alice.String(), incoming[0].RequesterId)
+
+	outgoing, _, err := h.ListPendingRequests(ctx, alice, true, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, bob.String(), outgoing[0].RecipientId)
+
+	// only the recipient can accept
+	_, err = h.AcceptContactRequest(ctx, alice, bob)
+	require.Error(t, err)
+	_, err = h.AcceptContactRequest(ctx, bob, alice)
+	require.NoError(t, err)
+
+	for _, pair := range [][2]gocql.UUID{{alice, bob}, {bob, alice}} {
+		contacts, _, err := h.ListContacts(ctx, pair[0], 10, nil)
+		require.NoError(t, err)
+		require.Len(t, contacts, 1)
+		require.Equal(t, pair[1].String(), contacts[0].UserId)
+	}
+
+	pending, _, err := h.ListPendingRequests(ctx, bob, false, 10, nil)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	require.NoError(t, h.RemoveContact(ctx, bob, alice))
+	contacts, _, err := h.ListContacts(ctx, alice, 10, nil)
+	require.NoError(t, err)
+	require.Empty(t, contacts)
+
+	require.Error(t, h.RemoveContact(ctx, bob, alice))
+	require.Error(t, h.DeclineContactRequest(ctx, bob, alice))
+}
+
+func TestBlockUser(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	h := handler.NewUserHandler(db)
+	alice, bob := gocql.TimeUUID(), gocql.TimeUUID()
+
+	_, err = h.SendContactRequest(ctx, alice, bob, "hi bob")
+	require.NoError(t, err)
+	_, err = h.AcceptContactRequest(ctx, bob, alice)
+	require.NoError(t, err)
+
+	require.NoError(t, h.BlockUser(ctx, bob, alice))
+
+	// blocking works in both directions and severs the contact
+	for _, pair := range [][2]gocql.UUID{{alice, bob}, {bob, alice}} {
+		blocked, err := blocklist.IsBlocked(ctx, db, pair[0], pair[1])
+		require.NoError(t, err)
+		require.True(t, blocked)
+
+		contacts, _, err := h.ListContacts(ctx, pair[0], 10, nil)
+		require.NoError(t, err)
+		require.Empty(t, contacts)
+	}
+
+	list, _, err := h.ListBlockedUsers(ctx, bob, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, alice.String(), list[0].UserId)
+
+	require.NoError(t, h.UnblockUser(ctx, bob, alice))
+	blocked, err := blocklist.IsBlocked(ctx, db, alice, bob)
+	require.NoError(t, err)
+	require.False(t, blocked)
+
+	require.NoError(t, h.MuteUser(ctx, alice, bob, 0))
+	muted, err := blocklist.IsMuted(ctx, db, alice, bob)
+	require.NoError(t, err)
+	require.True(t, muted)
+
+	require.NoError(t, h.UnmuteUser(ctx, alice, bob))
+	muted, err = blocklist.IsMuted(ctx, db, alice, bob)
+	require.NoError(t, err)
+	require.False(t, muted)
+}
+
+func TestSettings(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	h := handler.NewUserHandler(db)
+	userID := gocql.TimeUUID()
+	defaults := settings.Defaults(config.SettingsConfig{Theme: "system", Locale: "en", PushNotifications: true})
+
+	s, err := h.GetSettings(ctx, userID, defaults)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(defaults, s))
+
+	update := &userv1.Settings{
+		Theme:       userv1.Theme_THEME_DARK,
+		Privacy:     &userv1.PrivacySettings{HideEmail: true},
+		Preferences: map[string]string{"web.density": "compact", "web.sidebar": "open"},
+	}
+	require.NoError(t, h.UpdateSettings(ctx, userID, update, []string{"theme", "privacy.hide_email", "preferences"}))
+
+	s, err = h.GetSettings(ctx, userID, defaults)
+	require.NoError(t, err)
+	require.Equal(t, userv1.Theme_THEME_DARK, s.Theme)
+	require.Equal(t, "en", s.Locale)
+	require.True(t, s.Notifications.PushEnabled)
+	require.True(t, s.Privacy.HideEmail)
+	require.Len(t, s.Preferences, 2)
+
+	// a preference path without a value removes the key; theme reverts to default
+	require.NoError(t, h.UpdateSettings(ctx, userID, &userv1.Settings{}, []string{"theme", "preferences.web.sidebar"}))
+
+	s, err = h.GetSettings(ctx, userID, defaults)
+	require.NoError(t, err)
+	require.Equal(t, userv1.Theme_THEME_SYSTEM, s.Theme)
+	require.Equal(t, map[string]string{"web.density": "compact"}, s.Preferences)
+
+	privacy, err := h.PrivacySettings(ctx, []gocql.UUID{userID, gocql.TimeUUID()}, defaults.Privacy)
+	require.NoError(t, err)
+	require.Len(t, privacy, 2)
+	require.True(t, privacy[userID].HideEmail)
+}
+
+func TestSuspendUser(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	h := handler.NewUserHandler(db)
+	userID, admin := gocql.TimeUUID(), gocql.TimeUUID()
+	require.NoError(t, db.Query(`INSERT INTO chat.users (id, name, email, alias_name, created_at, updated_at, password)
+		VALUES (?, ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?)`,
+		userID, "Mallory", "mallory@example.com", "mal", "pwd").Exec())
+
+	_, err = h.SuspendUser(ctx, gocql.TimeUUID(), admin, "spam", time.Time{})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	suspension, err := h.Suspension(ctx, userID)
+	require.NoError(t, err)
+	require.Nil(t, suspension)
+
+	_, err = h.SuspendUser(ctx, userID, admin, "spam", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	suspension, err = h.Suspension(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, suspension)
+	require.Equal(t, "spam", suspension.Reason)
+	require.Equal(t, admin.String(), suspension.SuspendedBy)
+	require.NotNil(t, suspension.EndsAt)
+
+	require.NoError(t, h.UnsuspendUser(ctx, userID))
+	suspension, err = h.Suspension(ctx, userID)
+	require.NoError(t, err)
+	require.Nil(t, suspension)
+}
+
+func TestChangeLog(t *testing.T) {
+	ctx := context.Background()
+	db, err := getConn()
+	require.NoError(t, err)
+
+	h := handler.NewUserHandler(db)
+	since := gocql.MinTimeUUID(time.Now().Add(-time.Second))
+	userID := gocql.TimeUUID()
+	require.NoError(t, h.CreateUser(ctx, &userv1.User{
+		Id: userID.String(), Name: "Carol", AliasName: "caz", Email: "carol@example.com",
+	}, "pwd"))
+	_, err = h.UpdateProfile(ctx, userID.String(), &userv1.User{Bio: "hi"}, []string{"bio"})
+	require.NoError(t, err)
+
+	var entries []changelog.Entry
+	w := &changelog.Watcher{Scan: h.ScanChanges}
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	require.NoError(t, w.Watch(watchCtx, since, func(e changelog.Entry) error {
+		if e.UserID == userID {
+			entries = append(entries, e)
+		}
+		if len(entries) == 2 {
+			cancel()
+		}
+		return nil
+	}))
+
+	require.Len(t, entries, 2)
+	require.Equal(t, changelog.KindCreated, entries[0].Kind)
+	require.Equal(t, changelog.KindUpdated, entries[1].Kind)
+	require.Equal(t, []string{"bio"}, entries[1].Fields)
+}
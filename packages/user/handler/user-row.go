@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gocql/gocql"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// userColumns are the chat.users columns scanned into a userRow, in order.
+const userColumns = `id, name, alias_name, email, created_at, updated_at,
+	bio, status_text, status_emoji, status_expires_at, timezone, locale, avatar_url`
+
+// userRow holds the scan targets for a single chat.users row.
+type userRow struct {
+	id              gocql.UUID
+	name            string
+	aliasName       string
+	email           string
+	createdAt       time.Time
+	updatedAt       time.Time
+	bio             string
+	statusText      string
+	statusEmoji     string
+	statusExpiresAt time.Time
+	timezone        string
+	locale          string
+	avatarURL       string
+}
+
+// dest returns pointers matching userColumns for Scan.
+func (r *userRow) dest() []any {
+	return []any{
+		&r.id, &r.name, &r.aliasName, &r.email, &r.createdAt, &r.updatedAt,
+		&r.bio, &r.statusText, &r.statusEmoji, &r.statusExpiresAt, &r.timezone, &r.locale, &r.avatarURL,
+	}
+}
+
+// toProto converts the row, dropping a status whose expiry has passed.
+func (r *userRow) toProto() *userv1.User {
+	user := &userv1.User{
+		Id:          r.id.String(),
+		Name:        r.name,
+		AliasName:   r.aliasName,
+		Email:       r.email,
+		CreatedAt:   timestamppb.New(r.createdAt),
+		UpdatedAt:   timestamppb.New(r.updatedAt),
+		Bio:         r.bio,
+		StatusText:  r.statusText,
+		StatusEmoji: r.statusEmoji,
+		Timezone:    r.timezone,
+		Locale:      r.locale,
+		AvatarUrl:   r.avatarURL,
+	}
+
+	if !r.statusExpiresAt.IsZero() {
+		if time.Now().After(r.statusExpiresAt) {
+			user.StatusText = ""
+			user.StatusEmoji = ""
+		} else {
+			user.StatusExpiresAt = timestamppb.New(r.statusExpiresAt)
+		}
+	}
+
+	return user
+}
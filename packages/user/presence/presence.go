@@ -0,0 +1,193 @@
+// Package presence tracks which users are online using Redis keys that expire
+// unless refreshed by heartbeats.
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultTTL applies when the config leaves the heartbeat TTL unset.
+const DefaultTTL = 60 * time.Second
+
+// heartbeatsKey is a sorted set of online user ids scored by their last
+// heartbeat in unix milliseconds; the reaper uses it to find expired users.
+const heartbeatsKey = "presence:heartbeats"
+
+// claimBatch bounds how many expired users one ClaimExpired call takes.
+const claimBatch = 1000
+
+// heartbeatScript stores the new status and reports whether it differs from
+// the stored one, in one step so a concurrent claim cannot fall in between.
+var heartbeatScript = redis.NewScript(`
+local prev = redis.call('HGET', KEYS[1], 'status')
+redis.call('HSET', KEYS[1], 'status', ARGV[1], 'updated_at', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[4])
+if prev == ARGV[1] then
+  return 0
+end
+return 1
+`)
+
+// claimScript removes the users whose last heartbeat is at or before the
+// cutoff, with their status, and returns them with their scores. A heartbeat
+// either lands before and keeps the user, or after and finds no status, so
+// it is reported as a change.
+var claimScript = redis.NewScript(`
+local stale = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'WITHSCORES', 'LIMIT', 0, ARGV[2])
+for i = 1, #stale, 2 do
+  redis.call('ZREM', KEYS[1], stale[i])
+  redis.call('DEL', ARGV[3] .. stale[i])
+end
+return stale
+`)
+
+type Store struct {
+	Redis *redis.Client
+	TTL   time.Duration
+}
+
+// NewStore creates a Store whose entries lapse ttl after the last heartbeat.
+func NewStore(rdb *redis.Client, ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{Redis: rdb, TTL: ttl}
+}
+
+// statusKeyPrefix starts each user's status key; claimScript builds the keys
+// from it.
+const statusKeyPrefix = "presence:"
+
+func statusKey(userID string) string {
+	return statusKeyPrefix + userID
+}
+
+func channel(userID string) string {
+	return fmt.Sprintf("presence:events:%s", userID)
+}
+
+// Heartbeat records userID as online or away and refreshes its TTL. changed
+// reports whether the status differs from what was stored before.
+func (s *Store) Heartbeat(ctx context.Context, userID string, st userv1.PresenceStatus) (p *userv1.Presence, changed bool, err error) {
+	now := time.Now()
+	n, err := heartbeatScript.Run(ctx, s.Redis, []string{statusKey(userID), heartbeatsKey},
+		int32(st), now.UnixMilli(), s.TTL.Milliseconds(), userID).Int()
+	if err != nil {
+		return nil, false, err
+	}
+
+	p = &userv1.Presence{UserId: userID, Status: st, UpdatedAt: timestamppb.New(now)}
+	return p, n == 1, nil
+}
+
+// Offline removes userID's presence. wasOnline reports whether this call is the
+// one that took the user offline, so only one caller persists last-seen.
+func (s *Store) Offline(ctx context.Context, userID string) (wasOnline bool, err error) {
+	pipe := s.Redis.TxPipeline()
+	del := pipe.Del(ctx, statusKey(userID))
+	rem := pipe.ZRem(ctx, heartbeatsKey, userID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return del.Val() > 0 || rem.Val() > 0, nil
+}
+
+// Get returns the stored presence of each id. Users without a live entry are
+// reported offline with no last-seen; callers fill that in from Cassandra.
+func (s *Store) Get(ctx context.Context, ids []string) ([]*userv1.Presence, error) {
+	pipe := s.Redis.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, statusKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	out := make([]*userv1.Presence, len(ids))
+	for i, cmd := range cmds {
+		out[i] = &userv1.Presence{UserId: ids[i], Status: userv1.PresenceStatus_PRESENCE_STATUS_OFFLINE}
+
+		fields := cmd.Val()
+		st, err := strconv.Atoi(fields["status"])
+		if err != nil {
+			continue
+		}
+		out[i].Status = userv1.PresenceStatus(st)
+		if ms, err := strconv.ParseInt(fields["updated_at"], 10, 64); err == nil {
+			out[i].UpdatedAt = timestamppb.New(time.UnixMilli(ms))
+		}
+	}
+	return out, nil
+}
+
+// Expired is a user whose heartbeats stopped, with the time of the last one.
+type Expired struct {
+	UserID   string
+	LastSeen time.Time
+}
+
+// ClaimExpired removes users whose last heartbeat is older than the TTL and
+// returns them, up to claimBatch per call. Each user is returned to exactly
+// one caller across instances.
+func (s *Store) ClaimExpired(ctx context.Context, now time.Time) ([]Expired, error) {
+	cutoff := now.Add(-s.TTL).UnixMilli()
+	stale, err := claimScript.Run(ctx, s.Redis, []string{heartbeatsKey},
+		cutoff, claimBatch, statusKeyPrefix).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []Expired
+	for i := 0; i+1 < len(stale); i += 2 {
+		ms, err := strconv.ParseFloat(stale[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected heartbeat score %q: %w", stale[i+1], err)
+		}
+		claimed = append(claimed, Expired{UserID: stale[i], LastSeen: time.UnixMilli(int64(ms))})
+	}
+	return claimed, nil
+}
+
+// Publish notifies watchers of p.UserId about a presence change.
+func (s *Store) Publish(ctx context.Context, p *userv1.Presence) error {
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.Redis.Publish(ctx, channel(p.UserId), data).Err()
+}
+
+// Subscribe listens for presence changes of ids. The returned subscription is
+// confirmed, so no change published after Subscribe returns is missed.
+func (s *Store) Subscribe(ctx context.Context, ids []string) (*redis.PubSub, error) {
+	channels := make([]string, len(ids))
+	for i, id := range ids {
+		channels[i] = channel(id)
+	}
+	sub := s.Redis.Subscribe(ctx, channels...)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Decode parses a message delivered to a Subscribe channel.
+func Decode(msg *redis.Message) (*userv1.Presence, error) {
+	p := &userv1.Presence{}
+	if err := proto.Unmarshal([]byte(msg.Payload), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
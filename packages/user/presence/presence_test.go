@@ -0,0 +1,111 @@
+package presence_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/presence"
+)
+
+func newStore(t *testing.T, ttl time.Duration) (*presence.Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return presence.NewStore(rdb, ttl), mr
+}
+
+func TestHeartbeatAndGet(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newStore(t, time.Minute)
+
+	_, changed, err := store.Heartbeat(ctx, "alice", userv1.PresenceStatus_PRESENCE_STATUS_ONLINE)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	_, changed, err = store.Heartbeat(ctx, "alice", userv1.PresenceStatus_PRESENCE_STATUS_ONLINE)
+	require.NoError(t, err)
+	require.False(t, changed, "repeating a status is only a heartbeat")
+
+	presences, err := store.Get(ctx, []string{"alice", "bob"})
+	require.NoError(t, err)
+	require.Equal(t, userv1.PresenceStatus_PRESENCE_STATUS_ONLINE, presences[0].Status)
+	require.Equal(t, userv1.PresenceStatus_PRESENCE_STATUS_OFFLINE, presences[1].Status)
+
+	// the status key lapses with the TTL
+	mr.FastForward(2 * time.Minute)
+	presences, err = store.Get(ctx, []string{"alice"})
+	require.NoError(t, err)
+	require.Equal(t, userv1.PresenceStatus_PRESENCE_STATUS_OFFLINE, presences[0].Status)
+}
+
+func TestOffline(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newStore(t, time.Minute)
+
+	_, _, err := store.Heartbeat(ctx, "alice", userv1.PresenceStatus_PRESENCE_STATUS_AWAY)
+	require.NoError(t, err)
+
+	wasOnline, err := store.Offline(ctx, "alice")
+	require.NoError(t, err)
+	require.True(t, wasOnline)
+
+	wasOnline, err = store.Offline(ctx, "alice")
+	require.NoError(t, err)
+	require.False(t, wasOnline)
+}
+
+func TestClaimExpired(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newStore(t, time.Minute)
+
+	_, _, err := store.Heartbeat(ctx, "alice", userv1.PresenceStatus_PRESENCE_STATUS_ONLINE)
+	require.NoError(t, err)
+
+	expired, err := store.ClaimExpired(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	expired, err = store.ClaimExpired(ctx, time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "alice", expired[0].UserID)
+
+	// a second reaper finds nothing left to claim
+	expired, err = store.ClaimExpired(ctx, time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	// a heartbeat after the claim brings the user back as a change
+	_, changed, err := store.Heartbeat(ctx, "alice", userv1.PresenceStatus_PRESENCE_STATUS_ONLINE)
+	require.NoError(t, err)
+	require.True(t, changed)
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newStore(t, time.Minute)
+
+	sub, err := store.Subscribe(ctx, []string{"alice"})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	want := &userv1.Presence{UserId: "alice", Status: userv1.PresenceStatus_PRESENCE_STATUS_AWAY}
+	require.NoError(t, store.Publish(ctx, want))
+	require.NoError(t, store.Publish(ctx, &userv1.Presence{UserId: "bob"}))
+
+	select {
+	case msg := <-sub.Channel():
+		got, err := presence.Decode(msg)
+		require.NoError(t, err)
+		require.Equal(t, want.UserId, got.UserId)
+		require.Equal(t, want.Status, got.Status)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no presence event received")
+	}
+}
@@ -0,0 +1,167 @@
+// Package profile validates the user-editable fields of a userv1.User.
+package profile
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // runtime images ship without a zoneinfo database
+	"unicode/utf8"
+
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	MaxNameLength        = 64
+	MaxAliasNameLength   = 32
+	MaxBioLength         = 280
+	MaxStatusTextLength  = 100
+	MaxStatusEmojiLength = 16
+	MaxAvatarURLLength   = 2048
+)
+
+// EditablePaths are the User fields UpdateProfile may change.
+var EditablePaths = []string{
+	"name",
+	"alias_name",
+	"bio",
+	"status_text",
+	"status_emoji",
+	"status_expires_at",
+	"timezone",
+	"locale",
+	"avatar_url",
+}
+
+// Paths resolves an update mask to the fields to write. An empty mask selects
+// every editable field; unknown or read-only paths are rejected.
+func Paths(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return slices.Clone(EditablePaths), nil
+	}
+
+	paths := make([]string, 0, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		if !slices.Contains(EditablePaths, path) {
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// Validate checks the values of the fields named in paths.
+func Validate(p *userv1.User, paths []string) error {
+	for _, path := range paths {
+		var err error
+		switch path {
+		case "name":
+			err = checkRequired(path, p.GetName(), MaxNameLength)
+		case "alias_name":
+			err = checkRequired(path, p.GetAliasName(), MaxAliasNameLength)
+		case "bio":
+			err = checkLength(path, p.GetBio(), MaxBioLength)
+		case "status_text":
+			err = checkLength(path, p.GetStatusText(), MaxStatusTextLength)
+		case "status_emoji":
+			err = checkStatusEmoji(p.GetStatusEmoji())
+		case "status_expires_at":
+			err = checkStatusExpiry(p)
+		case "timezone":
+			err = checkTimezone(p.GetTimezone())
+		case "locale":
+			err = checkLocale(p.GetLocale())
+		case "avatar_url":
+			err = checkAvatarURL(p.GetAvatarUrl())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkRequired(field, value string, max int) error {
+	if strings.TrimSpace(value) == "" {
+		return status.Errorf(codes.InvalidArgument, "%s cannot be empty", field)
+	}
+	return checkLength(field, value, max)
+}
+
+func checkLength(field, value string, max int) error {
+	if !utf8.ValidString(value) {
+		return status.Errorf(codes.InvalidArgument, "%s must be valid UTF-8", field)
+	}
+	if n := utf8.RuneCountInString(value); n > max {
+		return status.Errorf(codes.InvalidArgument, "%s must be at most %d characters, got %d", field, max, n)
+	}
+	return nil
+}
+
+func checkStatusEmoji(value string) error {
+	if err := checkLength("status_emoji", value, MaxStatusEmojiLength); err != nil {
+		return err
+	}
+	if strings.ContainsFunc(value, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }) {
+		return status.Error(codes.InvalidArgument, "status_emoji must be a single emoji or :shortcode:")
+	}
+	return nil
+}
+
+func checkStatusExpiry(p *userv1.User) error {
+	if p.GetStatusExpiresAt() == nil {
+		return nil
+	}
+	if err := p.GetStatusExpiresAt().CheckValid(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid status_expires_at: %v", err)
+	}
+	if !p.GetStatusExpiresAt().AsTime().After(time.Now()) {
+		return status.Error(codes.InvalidArgument, "status_expires_at must be in the future")
+	}
+	return nil
+}
+
+func checkTimezone(value string) error {
+	if value == "" {
+		return nil
+	}
+	// LoadLocation accepts "Local", which means nothing to other clients
+	if value == "Local" {
+		return status.Error(codes.InvalidArgument, "timezone must be an IANA time zone name")
+	}
+	if _, err := time.LoadLocation(value); err != nil {
+		return status.Errorf(codes.InvalidArgument, "unknown timezone %q", value)
+	}
+	return nil
+}
+
+func checkLocale(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := language.Parse(value); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid locale %q: %v", value, err)
+	}
+	return nil
+}
+
+func checkAvatarURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > MaxAvatarURLLength {
+		return status.Errorf(codes.InvalidArgument, "avatar_url must be at most %d bytes", MaxAvatarURLLength)
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return status.Error(codes.InvalidArgument, "avatar_url must be an absolute http(s) URL")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package profile_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/user/profile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestPaths(t *testing.T) {
+	paths, err := profile.Paths(nil)
+	require.NoError(t, err)
+	require.Equal(t, profile.EditablePaths, paths)
+
+	paths, err = profile.Paths(&fieldmaskpb.FieldMask{Paths: []string{"bio", "bio", "locale"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bio", "locale"}, paths)
+
+	_, err = profile.Paths(&fieldmaskpb.FieldMask{Paths: []string{"email"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		profile *userv1.User
+		paths   []string
+		errors  bool
+	}{
+		{
+			name: "success:full_profile",
+			profile: &userv1.User{
+				Name:            "Alice",
+				AliasName:       "ali",
+				Bio:             "hello",
+				StatusText:      "in a meeting",
+				StatusEmoji:     "📅",
+				StatusExpiresAt: timestamppb.New(time.Now().Add(time.Hour)),
+				Timezone:        "Africa/Nairobi",
+				Locale:          "en-KE",
+				AvatarUrl:       "https://cdn.example.com/a.png",
+			},
+			paths:  profile.EditablePaths,
+			errors: false,
+		},
+		{
+			name:    "success:clear_optional_fields",
+			profile: &userv1.User{},
+			paths:   []string{"bio", "timezone", "locale", "avatar_url", "status_expires_at"},
+			errors:  false,
+		},
+		{
+			name:    "error:empty_name",
+			profile: &userv1.User{Name: "  "},
+			paths:   []string{"name"},
+			errors:  true,
+		},
+		{
+			name:    "error:bio_too_long",
+			profile: &userv1.User{Bio: string(make([]rune, profile.MaxBioLength+1))},
+			paths:   []string{"bio"},
+			errors:  true,
+		},
+		{
+			name:    "error:status_expiry_in_past",
+			profile: &userv1.User{StatusExpiresAt: timestamppb.New(time.Now().Add(-time.Minute))},
+			paths:   []string{"status_expires_at"},
+			errors:  true,
+		},
+		{
+			name:    "error:unknown_timezone",
+			profile: &userv1.User{Timezone: "Mars/Olympus"},
+			paths:   []string{"timezone"},
+			errors:  true,
+		},
+		{
+			name:    "error:invalid_locale",
+			profile: &userv1.User{Locale: "not a locale"},
+			paths:   []string{"locale"},
+			errors:  true,
+		},
+		{
+			name:    "error:relative_avatar_url",
+			profile: &userv1.User{AvatarUrl: "/avatars/me.png"},
+			paths:   []string{"avatar_url"},
+			errors:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := profile.Validate(tc.profile, tc.paths)
+			if tc.errors {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package settings resolves defaults for and validates updates to a user's
+// userv1.Settings.
+package settings
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	DefaultMaxPreferences = 100
+	MaxPreferenceKeyBytes = 128
+	MaxPreferenceValueLen = 1024
+
+	// PreferencesPath replaces the whole preferences map; PreferencePrefix
+	// followed by a key touches just that key.
+	PreferencesPath  = "preferences"
+	PreferencePrefix = PreferencesPath + "."
+)
+
+// EditablePaths are the typed Settings fields UpdateSettings may change.
+var EditablePaths = []string{
+	"theme",
+	"locale",
+	"notifications.push_enabled",
+	"notifications.email_enabled",
+	"notifications.sound_enabled",
+	"notifications.show_previews",
+	"privacy.hide_email",
+	"privacy.hide_last_seen",
+}
+
+// preferenceKey is "namespace.key"; the namespace keeps clients from treading
+// on each other's preferences.
+var preferenceKey = regexp.MustCompile(`^[a-z0-9_-]+\.[A-Za-z0-9_.-]+$`)
+
+// Defaults builds the settings a user has before changing anything.
+func Defaults(cfg config.SettingsConfig) *userv1.Settings {
+	theme := userv1.Theme_THEME_SYSTEM
+	if t, ok := userv1.Theme_value["THEME_"+strings.ToUpper(cfg.Theme)]; ok && t != 0 {
+		theme = userv1.Theme(t)
+	}
+
+	return &userv1.Settings{
+		Theme:  theme,
+		Locale: cfg.Locale,
+		Notifications: &userv1.NotificationSettings{
+			PushEnabled:  cfg.PushNotifications,
+			EmailEnabled: cfg.EmailNotifications,
+			SoundEnabled: cfg.NotificationSound,
+			ShowPreviews: cfg.ShowPreviews,
+		},
+		Privacy: &userv1.PrivacySettings{
+			HideEmail:    cfg.HideEmail,
+			HideLastSeen: cfg.HideLastSeen,
+		},
+		Preferences: map[string]string{},
+	}
+}
+
+// Paths resolves an update mask to the fields to write. "notifications" and
+// "privacy" expand to their fields. An empty mask selects every typed field
+// plus the preference keys present in s, so omitted preferences are kept.
+func Paths(mask *fieldmaskpb.FieldMask, s *userv1.Settings) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		paths := slices.Clone(EditablePaths)
+		for _, key := range slices.Sorted(maps.Keys(s.GetPreferences())) {
+			paths = append(paths, PreferencePrefix+key)
+		}
+		return paths, nil
+	}
+
+	var paths []string
+	add := func(path string) {
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
+	for _, path := range mask.GetPaths() {
+		switch {
+		case path == "notifications" || path == "privacy":
+			for _, p := range EditablePaths {
+				if strings.HasPrefix(p, path+".") {
+					add(p)
+				}
+			}
+		case path == PreferencesPath || slices.Contains(EditablePaths, path):
+			add(path)
+		case strings.HasPrefix(path, PreferencePrefix):
+			if err := checkPreferenceKey(strings.TrimPrefix(path, PreferencePrefix)); err != nil {
+				return nil, err
+			}
+			add(path)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+	}
+
+	if slices.Contains(paths, PreferencesPath) && slices.ContainsFunc(paths, isPreferencePath) {
+		return nil, status.Error(codes.InvalidArgument, "preferences cannot be replaced and updated by key in one request")
+	}
+	return paths, nil
+}
+
+// Validate checks the values of the fields named in paths.
+func Validate(s *userv1.Settings, paths []string) error {
+	for _, path := range paths {
+		switch {
+		case path == "theme":
+			if _, ok := userv1.Theme_name[int32(s.GetTheme())]; !ok {
+				return status.Errorf(codes.InvalidArgument, "unknown theme %d", s.GetTheme())
+			}
+		case path == "locale":
+			if s.GetLocale() == "" {
+				continue
+			}
+			if _, err := language.Parse(s.GetLocale()); err != nil {
+				return status.Errorf(codes.InvalidArgument, "invalid locale %q: %v", s.GetLocale(), err)
+			}
+		case path == PreferencesPath:
+			for key, value := range s.GetPreferences() {
+				if err := checkPreference(key, value); err != nil {
+					return err
+				}
+			}
+		case isPreferencePath(path):
+			key := strings.TrimPrefix(path, PreferencePrefix)
+			if value, ok := s.GetPreferences()[key]; ok {
+				if err := checkPreference(key, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// PreferenceCount is how many preferences current will hold once the update
+// in s restricted to paths is applied.
+func PreferenceCount(current map[string]string, s *userv1.Settings, paths []string) int {
+	if slices.Contains(paths, PreferencesPath) {
+		return len(s.GetPreferences())
+	}
+
+	n := len(current)
+	for _, path := range paths {
+		if !isPreferencePath(path) {
+			continue
+		}
+		key := strings.TrimPrefix(path, PreferencePrefix)
+		_, had := current[key]
+		_, has := s.GetPreferences()[key]
+		switch {
+		case has && !had:
+			n++
+		case had && !has:
+			n--
+		}
+	}
+	return n
+}
+
+func isPreferencePath(path string) bool {
+	return strings.HasPrefix(path, PreferencePrefix)
+}
+
+func checkPreference(key, value string) error {
+	if err := checkPreferenceKey(key); err != nil {
+		return err
+	}
+	if !utf8.ValidString(value) {
+		return status.Errorf(codes.InvalidArgument, "preference %q must be valid UTF-8", key)
+	}
+	if n := utf8.RuneCountInString(value); n > MaxPreferenceValueLen {
+		return status.Errorf(codes.InvalidArgument, "preference %q must be at most %d characters, got %d", key, MaxPreferenceValueLen, n)
+	}
+	return nil
+}
+
+func checkPreferenceKey(key string) error {
+	if len(key) > MaxPreferenceKeyBytes || !preferenceKey.MatchString(key) {
+		return status.Errorf(codes.InvalidArgument, "preference key %q must look like namespace.key and be at most %d bytes", key, MaxPreferenceKeyBytes)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package settings_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/config"
+	"github.com/yaninyzwitty/chat/packages/user/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestDefaults(t *testing.T) {
+	s := settings.Defaults(config.SettingsConfig{Theme: "dark", Locale: "en", HideLastSeen: true})
+	require.Equal(t, userv1.Theme_THEME_DARK, s.Theme)
+	require.Equal(t, "en", s.Locale)
+	require.True(t, s.Privacy.HideLastSeen)
+	require.False(t, s.Privacy.HideEmail)
+
+	s = settings.Defaults(config.SettingsConfig{Theme: "neon"})
+	require.Equal(t, userv1.Theme_THEME_SYSTEM, s.Theme)
+}
+
+func TestPaths(t *testing.T) {
+	s := &userv1.Settings{Preferences: map[string]string{"web.density": "compact"}}
+
+	paths, err := settings.Paths(nil, s)
+	require.NoError(t, err)
+	require.Equal(t, append(settings.EditablePaths, "preferences.web.density"), paths)
+
+	paths, err = settings.Paths(&fieldmaskpb.FieldMask{Paths: []string{"privacy", "theme", "theme"}}, s)
+	require.NoError(t, err)
+	require.Equal(t, []string{"privacy.hide_email", "privacy.hide_last_seen", "theme"}, paths)
+
+	for _, bad := range [][]string{
+		{"updated_at"},
+		{"preferences.nonamespace"},
+		{"preferences", "preferences.web.density"},
+	} {
+		_, err = settings.Paths(&fieldmaskpb.FieldMask{Paths: bad}, s)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), bad)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings *userv1.Settings
+		paths    []string
+		errors   bool
+	}{
+		{
+			name:     "success:typed_fields",
+			settings: &userv1.Settings{Theme: userv1.Theme_THEME_LIGHT, Locale: "sw-KE"},
+			paths:    []string{"theme", "locale"},
+			errors:   false,
+		},
+		{
+			name:     "success:reset_to_default",
+			settings: &userv1.Settings{},
+			paths:    []string{"theme", "locale"},
+			errors:   false,
+		},
+		{
+			name:     "error:unknown_theme",
+			settings: &userv1.Settings{Theme: 42},
+			paths:    []string{"theme"},
+			errors:   true,
+		},
+		{
+			name:     "error:bad_locale",
+			settings: &userv1.Settings{Locale: "not a locale"},
+			paths:    []string{"locale"},
+			errors:   true,
+		},
+		{
+			name:     "error:bad_preference_key",
+			settings: &userv1.Settings{Preferences: map[string]string{"Web Density": "x"}},
+			paths:    []string{"preferences"},
+			errors:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := settings.Validate(tc.settings, tc.paths)
+			if tc.errors {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPreferenceCount(t *testing.T) {
+	current := map[string]string{"web.a": "1", "web.b": "2"}
+	s := &userv1.Settings{Preferences: map[string]string{"web.a": "3", "web.c": "4"}}
+
+	// web.a overwritten, web.b removed, web.c added
+	n := settings.PreferenceCount(current, s, []string{"preferences.web.a", "preferences.web.b", "preferences.web.c"})
+	require.Equal(t, 2, n)
+
+	n = settings.PreferenceCount(current, s, []string{"preferences"})
+	require.Equal(t, 2, n)
+}
@@ -0,0 +1,223 @@
+// Package usercache is a read-through cache of users in two tiers: an
+// in-process LRU in front of Redis, which is shared by every instance.
+// Invalidations are broadcast over Redis pub/sub so each instance drops its
+// local copy too.
+package usercache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/redis/go-redis/v9"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Defaults apply to any Config field left unset.
+const (
+	DefaultSize        = 10000
+	DefaultLocalTTL    = 30 * time.Second
+	DefaultRedisTTL    = 10 * time.Minute
+	DefaultNegativeTTL = 30 * time.Second
+)
+
+// invalidateChannel carries the ids of users whose entries were dropped.
+const invalidateChannel = "usercache:invalidate"
+
+const (
+	metricName = "user"
+	tierLocal  = "local"
+	tierRedis  = "redis"
+)
+
+type Config struct {
+	// maximum number of users held in the in-process tier
+	Size     int
+	LocalTTL time.Duration
+	RedisTTL time.Duration
+	// how long a missing user is remembered as missing, in both tiers
+	NegativeTTL time.Duration
+}
+
+// entry is a cached lookup; a nil user records that the user does not exist.
+type entry struct {
+	user    *userv1.User
+	expires time.Time
+}
+
+type Cache struct {
+	local *lru.Cache[string, entry]
+	rdb   *redis.Client
+	cfg   Config
+	m     *monitoring.Metrics
+}
+
+// New creates a Cache backed by rdb that records hits and misses on m.
+func New(rdb *redis.Client, m *monitoring.Metrics, cfg Config) *Cache {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultSize
+	}
+	if cfg.LocalTTL <= 0 {
+		cfg.LocalTTL = DefaultLocalTTL
+	}
+	if cfg.RedisTTL <= 0 {
+		cfg.RedisTTL = DefaultRedisTTL
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = DefaultNegativeTTL
+	}
+
+	// lru.New only fails for a non-positive size, ruled out above
+	local, _ := lru.New[string, entry](cfg.Size)
+	return &Cache{local: local, rdb: rdb, cfg: cfg, m: m}
+}
+
+func key(id string) string {
+	return fmt.Sprintf("usercache:%s", id)
+}
+
+// Get returns the user with id from the first tier holding it, otherwise
+// from load, whose result fills both tiers. A NotFound from load is cached
+// for the negative TTL. Redis failures fall through to load rather than
+// failing the lookup.
+//
+// An entry filled just before a concurrent invalidation can outlive it, so
+// staleness is ultimately bounded by the TTLs.
+func (c *Cache) Get(ctx context.Context, id string, load func(context.Context) (*userv1.User, error)) (*userv1.User, error) {
+	if e, ok := c.local.Get(id); ok && time.Now().Before(e.expires) {
+		c.m.CacheHits.WithLabelValues(metricName, tierLocal).Inc()
+		return found(e.user)
+	}
+	c.m.CacheMisses.WithLabelValues(metricName, tierLocal).Inc()
+
+	raw, err := c.rdb.Get(ctx, key(id)).Bytes()
+	switch {
+	case err == nil:
+		user, err := decode(raw)
+		if err == nil {
+			c.m.CacheHits.WithLabelValues(metricName, tierRedis).Inc()
+			c.storeLocal(id, user)
+			return found(user)
+		}
+		slog.Warn("dropping malformed cached user", "user_id", id, "error", err)
+	case !errors.Is(err, redis.Nil):
+		slog.Warn("failed to read cached user", "user_id", id, "error", err)
+	}
+	c.m.CacheMisses.WithLabelValues(metricName, tierRedis).Inc()
+
+	user, err := load(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	ttl := c.cfg.RedisTTL
+	if user == nil {
+		ttl = c.cfg.NegativeTTL
+	}
+	if raw, err := encode(user); err != nil {
+		slog.Warn("failed to encode user for caching", "user_id", id, "error", err)
+	} else if err := c.rdb.Set(ctx, key(id), raw, ttl).Err(); err != nil {
+		slog.Warn("failed to cache user", "user_id", id, "error", err)
+	}
+	c.storeLocal(id, user)
+	return found(user)
+}
+
+// Invalidate drops ids from Redis and from the local tier of every instance.
+func (c *Cache) Invalidate(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = key(id)
+		c.local.Remove(id)
+	}
+
+	pipe := c.rdb.Pipeline()
+	pipe.Del(ctx, keys...)
+	for _, id := range ids {
+		pipe.Publish(ctx, invalidateChannel, id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to invalidate cached users: %w", err)
+	}
+	return nil
+}
+
+// Run drops local entries as other instances invalidate them, until ctx is
+// done. Entries cached while the subscription is down are only bounded by
+// the local TTL.
+func (c *Cache) Run(ctx context.Context) error {
+	sub := c.rdb.Subscribe(ctx, invalidateChannel)
+	defer func() {
+		if err := sub.Close(); err != nil {
+			slog.Warn("failed to close user cache subscription", "error", err)
+		}
+	}()
+
+	msgs := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				return errors.New("user cache subscription closed")
+			}
+			c.local.Remove(msg.Payload)
+		}
+	}
+}
+
+func (c *Cache) storeLocal(id string, user *userv1.User) {
+	ttl := c.cfg.LocalTTL
+	if user == nil {
+		ttl = min(ttl, c.cfg.NegativeTTL)
+	}
+	c.local.Add(id, entry{user: user, expires: time.Now().Add(ttl)})
+}
+
+// found hands out a copy, since callers redact the users they are given. A
+// status that expired while cached is dropped, as it is when read from the
+// database.
+func found(user *userv1.User) (*userv1.User, error) {
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	user = proto.CloneOf(user)
+	if exp := user.GetStatusExpiresAt(); exp != nil && time.Now().After(exp.AsTime()) {
+		user.StatusText = ""
+		user.StatusEmoji = ""
+		user.StatusExpiresAt = nil
+	}
+	return user, nil
+}
+
+// encode marshals user for Redis; a missing user is stored as an empty value,
+// which no real user marshals to as every user has an id.
+func encode(user *userv1.User) ([]byte, error) {
+	if user == nil {
+		return []byte{}, nil
+	}
+	return proto.Marshal(user)
+}
+
+func decode(raw []byte) (*userv1.User, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	user := &userv1.User{}
+	if err := proto.Unmarshal(raw, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
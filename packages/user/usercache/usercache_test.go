@@ -0,0 +1,141 @@
+package usercache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	userv1 "github.com/yaninyzwitty/chat/gen/user/v1"
+	"github.com/yaninyzwitty/chat/packages/shared/monitoring"
+	"github.com/yaninyzwitty/chat/packages/user/usercache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newCache(t *testing.T, mr *miniredis.Miniredis) (*usercache.Cache, *monitoring.Metrics) {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	m := monitoring.NewMetrics(prometheus.NewRegistry())
+	return usercache.New(rdb, m, usercache.Config{NegativeTTL: time.Minute}), m
+}
+
+// loader counts how often the cache falls through to the database.
+type loader struct {
+	calls int
+	user  *userv1.User
+}
+
+func (l *loader) load(context.Context) (*userv1.User, error) {
+	l.calls++
+	if l.user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return l.user, nil
+}
+
+func TestGetReadsThroughBothTiers(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	cache, m := newCache(t, mr)
+	db := &loader{user: &userv1.User{Id: "u1", Name: "Alice"}}
+
+	user, err := cache.Get(ctx, "u1", db.load)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user.Name)
+
+	// callers may redact what they get without touching the cached copy
+	user.Name = ""
+	user, err = cache.Get(ctx, "u1", db.load)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user.Name)
+	require.Equal(t, 1, db.calls)
+	require.Equal(t, 1.0, testutil.ToFloat64(m.CacheHits.WithLabelValues("user", "local")))
+
+	// a second instance is served from Redis
+	other, m2 := newCache(t, mr)
+	user, err = other.Get(ctx, "u1", db.load)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user.Name)
+	require.Equal(t, 1, db.calls)
+	require.Equal(t, 1.0, testutil.ToFloat64(m2.CacheHits.WithLabelValues("user", "redis")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m2.CacheMisses.WithLabelValues("user", "local")))
+}
+
+func TestGetCachesMissingUsers(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	cache, _ := newCache(t, mr)
+	db := &loader{}
+
+	for range 2 {
+		_, err := cache.Get(ctx, "ghost", db.load)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	}
+	require.Equal(t, 1, db.calls)
+
+	// the negative entry lapses in Redis with its own TTL
+	mr.FastForward(2 * time.Minute)
+	other, _ := newCache(t, mr)
+	_, err := other.Get(ctx, "ghost", db.load)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, 2, db.calls)
+}
+
+func TestInvalidateReachesOtherInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mr := miniredis.RunT(t)
+	writer, _ := newCache(t, mr)
+	reader, _ := newCache(t, mr)
+	db := &loader{user: &userv1.User{Id: "u1", Name: "Alice"}}
+
+	done := make(chan error, 1)
+	go func() { done <- reader.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		return len(mr.PubSubChannels("usercache:*")) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := reader.Get(ctx, "u1", db.load)
+	require.NoError(t, err)
+
+	db.user = &userv1.User{Id: "u1", Name: "Alicia"}
+	require.NoError(t, writer.Invalidate(ctx, "u1"))
+
+	require.Eventually(t, func() bool {
+		user, err := reader.Get(ctx, "u1", db.load)
+		return err == nil && user.Name == "Alicia"
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestGetDropsExpiredStatus(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	cache, _ := newCache(t, mr)
+	db := &loader{user: &userv1.User{
+		Id:              "u1",
+		StatusText:      "in a meeting",
+		StatusExpiresAt: timestamppb.New(time.Now().Add(50 * time.Millisecond)),
+	}}
+
+	user, err := cache.Get(ctx, "u1", db.load)
+	require.NoError(t, err)
+	require.Equal(t, "in a meeting", user.StatusText)
+
+	// still cached, but the status has lapsed
+	time.Sleep(100 * time.Millisecond)
+	user, err = cache.Get(ctx, "u1", db.load)
+	require.NoError(t, err)
+	require.Equal(t, 1, db.calls)
+	require.Empty(t, user.StatusText)
+	require.Nil(t, user.StatusExpiresAt)
+}